@@ -6,92 +6,70 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
 
 	"github.com/pboyd04/gocsdl/pkg/csdl"
-	"github.com/pboyd04/gocsdl/pkg/odata"
+	"github.com/pboyd04/gocsdl/pkg/generator"
 )
 
 func main() {
 	ignoreCollections := flag.Bool("ignore-collections", false, "ignore collection resources")
 	individualFiles := flag.Bool("individual-files", true, "generate individual files")
 	packageName := flag.String("package-name", "standard", "package name for the generated file(s)")
+	outDir := flag.String("out", ".", "directory to write the generated file(s) to, created if it doesn't exist")
+	client := flag.Bool("client", false, "generate navigation properties as Link[T] along with a client to follow them")
+	server := flag.Bool("server", false, "generate interfaces for each resource and a router that serves them")
+	strictEnums := flag.Bool("strict-enums", false, "fail to unmarshal enum values that the schema doesn't define")
+	fieldVersions := flag.Bool("field-versions", false, "generate the version of the schema each field was added in along with ValidFields")
+	check := flag.Bool("check", false, "don't write anything, print a diff and exit non-zero if the files in -out are out of date")
+	warningsAreErrors := flag.Bool("werror", false, "treat warnings as errors, nothing is generated if there are any")
 	flag.Parse()
 	leftOverArgs := flag.Args()
 	if len(leftOverArgs) == 0 {
 		fmt.Printf("No file specified\n")
 	}
-	if !*ignoreCollections || !*individualFiles {
-		fmt.Printf("Unimplemented\n")
-	}
 	parser := csdl.NewParser()
 	parser.IgnoreCollections = *ignoreCollections
 	for _, fileName := range leftOverArgs {
 		processFile(fileName, parser)
 	}
-	types, err := parser.Parse()
+	gen := generator.New(*packageName)
+	gen.IndividualFiles = *individualFiles
+	gen.WarningsAreErrors = *warningsAreErrors
+	gen.Client = *client
+	gen.Server = *server
+	gen.StrictEnums = *strictEnums
+	gen.FieldVersions = *fieldVersions
+	files, diagnostics, err := gen.Generate(parser)
+	for _, diagnostic := range diagnostics {
+		fmt.Println(diagnostic)
+	}
 	if err != nil {
-		fmt.Printf("Error parsing CSDL: %s\n", err)
+		fmt.Printf("Error generating code: %s\n", err)
 		os.Exit(1)
 	}
-	parser.Fold(types)
-	if *individualFiles {
-		// Generate individual files
-		// Create the basic types...
-		err = odata.GenBoilerPlate(*packageName)
+	if *check {
+		diff, err := generator.Diff(*outDir, files)
 		if err != nil {
-			fmt.Printf("Error generating boilerplate: %s\n", err)
+			fmt.Printf("Error comparing files: %s\n", err)
 			os.Exit(1)
 		}
-		files := map[string]*csdl.File{}
-		for name, t := range types {
-			prefix := splitNamespacePrefix(name)
-			file, ok := files[prefix]
-			if !ok {
-				file = csdl.NewFile(*packageName)
-				files[prefix] = file
-			}
-			err = file.AddType(t)
-			if err != nil {
-				fmt.Printf("Error adding type %s to file %s: %s\n", name, prefix, err)
-				os.Exit(1)
-			}
-		}
-		for prefix, file := range files {
-			fileName := prefix + ".go"
-			data, err := file.Flush(types)
-			if err != nil {
-				fmt.Printf("Error generating file %s: %s\n", fileName, err)
-				os.Exit(1)
-			}
-			osFile, err := os.Create(fileName)
-			if err != nil {
-				fmt.Printf("Error creating file %s: %s\n", fileName, err)
-				os.Exit(1)
-			}
-			//nolint:errcheck // Ignore error on close, not sure what we can do about it
-			defer osFile.Close()
-			_, err = osFile.Write(data)
-			if err != nil {
-				fmt.Printf("Error writing file %s: %s\n", fileName, err)
-				os.Exit(1)
-			}
+		if diff != "" {
+			fmt.Print(diff)
+			os.Exit(1)
 		}
+		return
 	}
-}
-
-func splitNamespacePrefix(name string) string {
-	index := strings.Index(name, ".")
-	if index == -1 {
-		return name
+	err = generator.WriteFiles(*outDir, files)
+	if err != nil {
+		fmt.Printf("Error writing files: %s\n", err)
+		os.Exit(1)
 	}
-	return name[:index]
 }
 
 func processFile(fileName string, parser *csdl.Parser) {
 	ext := filepath.Ext(fileName)
 	switch ext {
-	case ".xml":
+	case ".xml", ".json":
 		// Process CSDL File...
 		file, err := os.Open(fileName)
 		if err != nil {
@@ -118,6 +96,7 @@ func processZipFile(fileName string, parser *csdl.Parser) {
 	// defer r.Close()
 	for _, f := range r.File {
 		// Skip the pdfs, html, and directories...
+		// The .json files in the DMTF bundles are JSON Schema, not CSDL, so only XML is read from zips
 		if filepath.Ext(f.Name) != ".xml" {
 			continue
 		}
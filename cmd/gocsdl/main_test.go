@@ -0,0 +1,71 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestMain runs main instead of the tests when GOCSDL_MAIN is set, so the tests can run the
+// command and see its exit code
+func TestMain(m *testing.M) {
+	if os.Getenv("GOCSDL_MAIN") != "" {
+		os.Args = append([]string{"gocsdl"}, os.Args[1:]...)
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// gocsdl runs the command with args and returns its output and exit code
+func gocsdl(t *testing.T, args ...string) (string, int) {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = append(os.Environ(), "GOCSDL_MAIN=1")
+	out, err := cmd.CombinedOutput()
+	exitErr := &exec.ExitError{}
+	if errors.As(err, &exitErr) {
+		return string(out), exitErr.ExitCode()
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out), 0
+}
+
+func TestCheck(t *testing.T) {
+	dir := t.TempDir()
+	fixtures, err := filepath.Glob(filepath.Join("..", "..", "pkg", "generator", "testdata", "redfish", "*.xml"))
+	if err != nil || len(fixtures) == 0 {
+		t.Fatalf("no fixtures: %v", err)
+	}
+	args := append([]string{"-out", dir}, fixtures...)
+	out, code := gocsdl(t, append([]string{"-check"}, args...)...)
+	if code != 1 || !strings.Contains(out, "+++ "+filepath.Join(dir, "Chassis.go")) {
+		t.Errorf("-check before generating exited %d, want 1 and a diff adding Chassis.go:\n%s", code, out)
+	}
+	_, err = os.Stat(filepath.Join(dir, "Chassis.go"))
+	if err == nil {
+		t.Error("-check wrote Chassis.go")
+	}
+	out, code = gocsdl(t, args...)
+	if code != 0 {
+		t.Fatalf("generating exited %d:\n%s", code, out)
+	}
+	out, code = gocsdl(t, append([]string{"-check"}, args...)...)
+	if code != 0 || strings.Contains(out, "+++ ") {
+		t.Errorf("-check after generating exited %d, want 0 and no diff:\n%s", code, out)
+	}
+	chassis := filepath.Join(dir, "Chassis.go")
+	err = os.WriteFile(chassis, []byte("package standard\n"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, code = gocsdl(t, append([]string{"-check"}, args...)...)
+	if code != 1 || !strings.Contains(out, "--- "+chassis) {
+		t.Errorf("-check with a stale Chassis.go exited %d, want 1 and a diff of it:\n%s", code, out)
+	}
+}
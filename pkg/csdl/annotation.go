@@ -0,0 +1,225 @@
+package csdl
+
+import (
+	"go/ast"
+	"slices"
+	"strings"
+)
+
+// Terms use the aliases in knownVocabularies, whatever the document included them as
+const (
+	descriptionTerm      = "OData.Description"
+	longDescriptionTerm  = "OData.LongDescription"
+	permissionsTerm      = "OData.Permissions"
+	patternTerm          = "Validation.Pattern"
+	minimumTerm          = "Validation.Minimum"
+	maximumTerm          = "Validation.Maximum"
+	requiredTerm         = "Redfish.Required"
+	requiredOnCreateTerm = "Redfish.RequiredOnCreate"
+	urisTerm             = "Redfish.Uris"
+	deprecatedTerm       = "Redfish.Deprecated"
+	revisionsTerm        = "Redfish.Revisions"
+)
+
+// enumTerms are the enum types of the terms whose values are enum members, along with the ones of
+// the properties of their records like Redfish.Revisions/Kind. CSDL JSON writes enum members as
+// plain strings like "Read,Write" so only the term's type tells them apart from strings. The types
+// come from the loaded vocabularies, these are for the terms of the ones that aren't loaded.
+var enumTerms = map[string]string{
+	permissionsTerm:         "OData.Permission",
+	revisionsTerm + "/Kind": "Redfish.RevisionKind",
+	"Redfish.ReleaseStatus": "Redfish.ReleaseStatusType",
+	"Capabilities.NavigationRestrictions/Navigability": "Capabilities.NavigationType",
+}
+
+// enumMembers returns the value of an annotation with the strings that the term's type says are
+// enum members turned into EnumMember expressions. Unqualified members are qualified by the enum
+// type so they read the same as the XML form. The expression isn't modified.
+func enumMembers(expr Expr, term string, v *vocabulary) Expr {
+	return convertEnumMembers(expr, v.termType(term))
+}
+
+func convertEnumMembers(expr Expr, valueType valueType) Expr {
+	switch expr := expr.(type) {
+	case Constant:
+		enumType, ok := valueType.enumType()
+		if !ok || expr.Kind != StringKind {
+			return expr
+		}
+		members := strings.FieldsFunc(expr.Value, isEnumSeparator)
+		for i, member := range members {
+			if !strings.Contains(member, "/") {
+				members[i] = enumType + "/" + member
+			}
+		}
+		return Constant{Kind: EnumMemberKind, Value: strings.Join(members, " ")}
+	case Collection:
+		items := make([]Expr, 0, len(expr.Items))
+		for _, item := range expr.Items {
+			items = append(items, convertEnumMembers(item, valueType))
+		}
+		return Collection{Items: items}
+	case Record:
+		propertyValues := slices.Clone(expr.PropertyValue)
+		for i := range propertyValues {
+			propertyValues[i].Value = convertEnumMembers(propertyValues[i].Value, valueType.property(propertyValues[i].Property, expr.Type))
+		}
+		expr.PropertyValue = propertyValues
+		return expr
+	}
+	return expr
+}
+
+// commentWidth is where doc comments are wrapped, not counting the "// "
+const commentWidth = 77
+
+// findAnnotation returns the first annotation that matches one of the terms
+func findAnnotation(annotations []Annotation, terms ...string) (Annotation, bool) {
+	for _, annotation := range annotations {
+		for _, term := range terms {
+			if annotation.Term == term {
+				return annotation, true
+			}
+		}
+	}
+	return Annotation{}, false
+}
+
+// annotationValue returns the value of an annotation, nil if it doesn't have one or it isn't
+// constant
+func annotationValue(annotation Annotation) any {
+	value, err := Evaluate(annotation.Expr)
+	if err != nil {
+		return nil
+	}
+	return value
+}
+
+// annotationString returns the string value of an annotation, or "" if it isn't a string
+func annotationString(annotation Annotation) string {
+	value, _ := annotationValue(annotation).(string)
+	return value
+}
+
+// descriptions returns the Description and LongDescription annotation text
+func descriptions(annotations []Annotation) (string, string) {
+	description, _ := findAnnotation(annotations, descriptionTerm)
+	longDescription, _ := findAnnotation(annotations, longDescriptionTerm)
+	return annotationString(description), annotationString(longDescription)
+}
+
+// deprecation returns the "Deprecated:" paragraph of a doc comment for an element that is marked
+// by Redfish.Deprecated or has a Deprecated revision in Redfish.Revisions, or "" if it isn't. The
+// text is from Redfish.Deprecated, or the revision's description, after the version of the revision.
+func deprecation(annotations []Annotation) string {
+	text := ""
+	deprecated, isDeprecated := findAnnotation(annotations, deprecatedTerm)
+	if isDeprecated {
+		text = annotationString(deprecated)
+	}
+	version := ""
+	revisions, _ := findAnnotation(annotations, revisionsTerm)
+	items, _ := annotationValue(revisions).([]any)
+	for _, item := range items {
+		revision, _ := item.(map[string]any)
+		kind, _ := revision["Kind"].(EnumValue)
+		// The enum member is qualified by whatever alias the document uses for RedfishExtensions
+		if len(kind) != 1 || !strings.HasSuffix(kind[0], "RevisionKind/Deprecated") {
+			continue
+		}
+		isDeprecated = true
+		version, _ = revision["Version"].(string)
+		if text == "" {
+			text, _ = revision["Description"].(string)
+		}
+	}
+	switch {
+	case !isDeprecated:
+		return ""
+	case version == "" && text == "":
+		return "Deprecated: this is deprecated in the schema."
+	case version == "":
+		return "Deprecated: " + text
+	case text == "":
+		return "Deprecated: since " + version + "."
+	}
+	return "Deprecated: since " + version + ". " + text
+}
+
+// annotationBool returns the Bool value of the annotation, an annotation without a value is true
+// since that is the default for tag terms like Redfish.Required
+func annotationBool(annotation Annotation) bool {
+	value, ok := annotationValue(annotation).(bool)
+	return !ok || value
+}
+
+// hasTag returns true if one of the terms is applied and not set to false
+func hasTag(annotations []Annotation, terms ...string) bool {
+	annotation, ok := findAnnotation(annotations, terms...)
+	return ok && annotationBool(annotation)
+}
+
+// annotationStrings returns the strings in a collection annotation
+func annotationStrings(annotations []Annotation, term string) []string {
+	annotation, ok := findAnnotation(annotations, term)
+	if !ok {
+		return nil
+	}
+	items, _ := annotationValue(annotation).([]any)
+	strs := []string{}
+	for _, item := range items {
+		str, ok := item.(string)
+		if ok {
+			strs = append(strs, str)
+		}
+	}
+	return strs
+}
+
+// validations returns the Pattern, Minimum and Maximum annotation values, the numbers are nil if not set
+func validations(annotations []Annotation) (string, *float64, *float64) {
+	pattern, _ := findAnnotation(annotations, patternTerm)
+	minimum, _ := findAnnotation(annotations, minimumTerm)
+	maximum, _ := findAnnotation(annotations, maximumTerm)
+	return annotationString(pattern), annotationNumber(minimum), annotationNumber(maximum)
+}
+
+// annotationNumber returns the Int or Decimal value of the annotation as a float64
+func annotationNumber(annotation Annotation) *float64 {
+	switch value := annotationValue(annotation).(type) {
+	case int64:
+		number := float64(value)
+		return &number
+	case float64:
+		return &value
+	}
+	return nil
+}
+
+// docComment turns each non-empty paragraph into a wrapped doc comment, or nil if there is no text
+func docComment(paragraphs ...string) *ast.CommentGroup {
+	group := &ast.CommentGroup{}
+	for _, paragraph := range paragraphs {
+		words := strings.Fields(paragraph)
+		if len(words) == 0 {
+			continue
+		}
+		if len(group.List) != 0 {
+			group.List = append(group.List, &ast.Comment{Text: "//"})
+		}
+		line := words[0]
+		for _, word := range words[1:] {
+			if len(line)+1+len(word) > commentWidth {
+				group.List = append(group.List, &ast.Comment{Text: "// " + line})
+				line = word
+				continue
+			}
+			line += " " + word
+		}
+		group.List = append(group.List, &ast.Comment{Text: "// " + line})
+	}
+	if len(group.List) == 0 {
+		return nil
+	}
+	return group
+}
@@ -0,0 +1,190 @@
+package csdl
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJSONEnumMembers(t *testing.T) {
+	document := `{
+		"$Version": "4.01",
+		"$Reference": {
+			"Core": {"$Include": [{"$Namespace": "Org.OData.Core.V1", "$Alias": "Core"}]},
+			"Redfish": {"$Include": [{"$Namespace": "RedfishExtensions.v1_0_0", "$Alias": "Redfish"}]}
+		},
+		"Test": {
+			"Thing": {
+				"$Kind": "ComplexType",
+				"ReadWrite": {"@Core.Permissions": "Read,Write"},
+				"Read": {"@Core.Permissions": "Read"},
+				"Qualified": {"@Core.Permissions": "OData.Permission/Read OData.Permission/Write"},
+				"Slash": {"@Core.Description": "Uses TCP/IP."},
+				"Old": {"@Redfish.Revisions": [{"Kind": "Deprecated", "Version": "v1_2_0"}]}
+			}
+		}
+	}`
+	edmx, err := DecodeJSON(strings.NewReader(document))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]any{
+		"Test.Thing/ReadWrite": EnumValue{"OData.Permission/Read", "OData.Permission/Write"},
+		"Test.Thing/Read":      EnumValue{"OData.Permission/Read"},
+		"Test.Thing/Qualified": EnumValue{"OData.Permission/Read", "OData.Permission/Write"},
+		"Test.Thing/Slash":     "Uses TCP/IP.",
+		"Test.Thing/Old":       []any{map[string]any{"Kind": EnumValue{"Redfish.RevisionKind/Deprecated"}, "Version": "v1_2_0"}},
+	}
+	for target, want := range tests {
+		annotations := edmx.AnnotationsOf(target)
+		if len(annotations) != 1 {
+			t.Errorf("%s has %d annotations, want 1", target, len(annotations))
+			continue
+		}
+		got, err := Evaluate(annotations[0].Expr)
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("%s = %#v, %v, want %#v", target, got, err, want)
+		}
+	}
+}
+
+func TestVocabularyEnumMembers(t *testing.T) {
+	jsonDocument := `{
+		"$Version": "4.01",
+		"Contoso.v1_0_0": {
+			"$Alias": "Contoso",
+			"Color": {"$Kind": "EnumType", "Red": 0, "Blue": 1},
+			"Paint": {"$Kind": "ComplexType", "Color": {"$Type": "Contoso.Color"}, "Name": {}},
+			"Gloss": {"$Kind": "ComplexType", "$BaseType": "Contoso.Paint", "Shine": {"$Type": "Edm.Int64"}},
+			"Favorite": {"$Kind": "Term", "$Type": "Contoso.Color"},
+			"Paints": {"$Kind": "Term", "$Type": "Contoso.Paint", "$Collection": true},
+			"Label": {"$Kind": "Term"}
+		},
+		"Test": {
+			"Thing": {
+				"$Kind": "ComplexType",
+				"Favorite": {"@Contoso.Favorite": "Blue"},
+				"Paints": {"@Contoso.Paints": [{"Color": "Red", "Name": "Red"}, {"@type": "Contoso.Gloss", "Color": "Blue", "Shine": 2}]},
+				"Label": {"@Contoso.Label": "Red"}
+			}
+		}
+	}`
+	xmlDocument := `<edmx:Edmx xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx" Version="4.0">
+		<edmx:DataServices>
+			<Schema xmlns="http://docs.oasis-open.org/odata/ns/edm" Namespace="Contoso.v1_0_0" Alias="Contoso">
+				<EnumType Name="Color"><Member Name="Red"/><Member Name="Blue"/></EnumType>
+				<ComplexType Name="Paint"><Property Name="Color" Type="Contoso.Color"/><Property Name="Name" Type="Edm.String"/></ComplexType>
+				<ComplexType Name="Gloss" BaseType="Contoso.Paint"><Property Name="Shine" Type="Edm.Int64"/></ComplexType>
+				<Term Name="Favorite" Type="Contoso.Color"/>
+				<Term Name="Paints" Type="Collection(Contoso.Paint)"/>
+				<Term Name="Label" Type="Edm.String"/>
+			</Schema>
+			<Schema xmlns="http://docs.oasis-open.org/odata/ns/edm" Namespace="Test">
+				<ComplexType Name="Thing">
+					<Property Name="Favorite" Type="Edm.String">
+						<Annotation Term="Contoso.Favorite" EnumMember="Contoso.v1_0_0.Color/Blue"/>
+					</Property>
+					<Property Name="Paints" Type="Edm.String">
+						<Annotation Term="Contoso.Paints">
+							<Collection>
+								<Record><PropertyValue Property="Color" EnumMember="Contoso.v1_0_0.Color/Red"/><PropertyValue Property="Name" String="Red"/></Record>
+								<Record Type="Contoso.Gloss"><PropertyValue Property="Color" EnumMember="Contoso.v1_0_0.Color/Blue"/><PropertyValue Property="Shine" Int="2"/></Record>
+							</Collection>
+						</Annotation>
+					</Property>
+					<Property Name="Label" Type="Edm.String">
+						<Annotation Term="Contoso.Label" String="Red"/>
+					</Property>
+				</ComplexType>
+			</Schema>
+		</edmx:DataServices>
+	</edmx:Edmx>`
+	jsonEdmx, err := DecodeJSON(strings.NewReader(jsonDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	xmlEdmx, err := decodeFile(strings.NewReader(xmlDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]any{
+		"Test.Thing/Favorite": EnumValue{"Contoso.v1_0_0.Color/Blue"},
+		"Test.Thing/Paints": []any{
+			map[string]any{"Color": EnumValue{"Contoso.v1_0_0.Color/Red"}, "Name": "Red"},
+			map[string]any{"Color": EnumValue{"Contoso.v1_0_0.Color/Blue"}, "Shine": int64(2)},
+		},
+		"Test.Thing/Label": "Red",
+	}
+	for name, edmx := range map[string]*Edmx{"json": jsonEdmx, "xml": xmlEdmx} {
+		for target, want := range tests {
+			annotations := edmx.AnnotationsOf(target)
+			if len(annotations) != 1 {
+				t.Errorf("%s: %s has %d annotations, want 1", name, target, len(annotations))
+				continue
+			}
+			got, err := Evaluate(annotations[0].Expr)
+			if err != nil || !reflect.DeepEqual(got, want) {
+				t.Errorf("%s: %s = %#v, %v, want %#v", name, target, got, err, want)
+			}
+		}
+	}
+}
+
+func TestAnnotationConstantFields(t *testing.T) {
+	xmlDocument := `<edmx:Edmx xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx" Version="4.0">
+		<edmx:DataServices>
+			<Schema xmlns="http://docs.oasis-open.org/odata/ns/edm" Namespace="Test">
+				<ComplexType Name="Thing">
+					<Annotation Term="Test.String" String="text"/>
+					<Annotation Term="Test.EnumMember"><EnumMember>Test.Color/Red</EnumMember></Annotation>
+					<Annotation Term="Test.Bool" Bool="true"/>
+					<Annotation Term="Test.Int" Int="42"/>
+					<Annotation Term="Test.Decimal" Decimal="1.5"/>
+					<Annotation Term="Test.Collection"><Collection><String>text</String></Collection></Annotation>
+				</ComplexType>
+			</Schema>
+		</edmx:DataServices>
+	</edmx:Edmx>`
+	jsonDocument := `{
+		"$Version": "4.01",
+		"Test": {
+			"Thing": {
+				"$Kind": "ComplexType",
+				"@Test.String": "text",
+				"@Test.EnumMember": {"$EnumMember": "Test.Color/Red"},
+				"@Test.Bool": true,
+				"@Test.Int": 42,
+				"@Test.Decimal": 1.5,
+				"@Test.Collection": ["text"]
+			}
+		}
+	}`
+	want := []Annotation{
+		{Term: "Test.String", String: "text"},
+		{Term: "Test.EnumMember", EnumMember: "Test.Color/Red"},
+		{Term: "Test.Bool", Bool: true},
+		{Term: "Test.Int", Int: 42},
+		{Term: "Test.Decimal", Decimal: 1.5},
+		{Term: "Test.Collection"},
+	}
+	xmlEdmx, err := decodeFile(strings.NewReader(xmlDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	jsonEdmx, err := DecodeJSON(strings.NewReader(jsonDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, edmx := range map[string]*Edmx{"xml": xmlEdmx, "json": jsonEdmx} {
+		annotations := edmx.AnnotationsOf("Test.Thing")
+		if len(annotations) != len(want) {
+			t.Fatalf("%s: %d annotations, want %d", name, len(annotations), len(want))
+		}
+		for i, annotation := range annotations {
+			annotation.Qualifier, annotation.Expr, annotation.Annotation = "", nil, nil
+			if !reflect.DeepEqual(annotation, want[i]) {
+				t.Errorf("%s: %#v, want %#v", name, annotation, want[i])
+			}
+		}
+	}
+}
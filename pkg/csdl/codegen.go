@@ -5,6 +5,8 @@ import (
 	"go/ast"
 	"go/format"
 	"go/token"
+	"maps"
+	"slices"
 	"strings"
 )
 
@@ -30,27 +32,45 @@ func NewFile(packageName string) *File {
 	return ret
 }
 
+// NewFileFromSource starts a file with existing Go source, such as the odata boilerplate,
+// so that everything can be generated into a single file
+func NewFileFromSource(src []byte) *File {
+	ret := &File{
+		w:       bytes.NewBuffer(nil),
+		fileSet: token.NewFileSet(),
+		types:   make(map[string]*Type),
+	}
+	ret.w.Write(src)
+	ret.w.WriteString("\n")
+	return ret
+}
+
 func (f *File) AddType(t *Type) error {
 	if strings.HasPrefix(t.Namespace, "MessageRegistry") && t.Name == "Message" {
 		// This is a special case for the Message type, which is replicated
 		return nil
 	}
-	existing, ok := f.types[t.Name]
+	// Key on the generated name so that types from different namespaces don't collide
+	// when everything goes into one file
+	name := t.GoTypeName()
+	existing, ok := f.types[name]
 	if ok {
-		if len(existing.Properties) > len(t.Properties) || len(existing.Members) > len(t.Members) {
+		if len(existing.Properties) > len(t.Properties) || len(existing.Members) > len(t.Members) || len(existing.Actions) > len(t.Actions) {
 			// We already have a more complete type, so just skip this one
 			return nil
 		}
 	}
-	f.types[t.Name] = t
+	f.types[name] = t
 	return nil
 }
 
 func (f *File) Flush(allTypes map[string]*Type) ([]byte, error) {
-	for _, typeData := range f.types {
+	// Sort by the generated name so that the output is the same every run
+	for _, name := range slices.Sorted(maps.Keys(f.types)) {
+		typeData := f.types[name]
 		typeTokens := typeData.Node(allTypes)
 		for _, typeToken := range typeTokens {
-			err := format.Node(f.w, f.fileSet, typeToken)
+			err := f.writeNode(typeToken)
 			if err != nil {
 				return nil, err
 			}
@@ -60,6 +80,137 @@ func (f *File) Flush(allTypes map[string]*Type) ([]byte, error) {
 			}
 		}
 	}
+	registry, ok := f.registryNode(allTypes)
+	if ok {
+		err := f.writeNode(registry)
+		if err != nil {
+			return nil, err
+		}
+	}
 	// We should be good, but run it through the formatter one more time to be sure...
 	return format.Source(f.w.Bytes())
 }
+
+// writeNode writes a top level declaration to the file. go/printer can't place doc comments on
+// nodes that don't have positions, so declarations are written out by hand and cleaned up by the
+// format.Source call in Flush
+func (f *File) writeNode(node ast.Node) error {
+	src, ok := node.(*sourceNode)
+	if ok {
+		f.w.WriteString(src.src)
+		return nil
+	}
+	decl, ok := node.(*ast.GenDecl)
+	if !ok {
+		return format.Node(f.w, f.fileSet, node)
+	}
+	f.writeDoc(decl.Doc)
+	f.w.WriteString(decl.Tok.String() + " ")
+	// A lone spec with a doc comment is grouped so the comment isn't on the same line as the keyword
+	grouped := len(decl.Specs) != 1 || specDoc(decl.Specs[0]) != nil
+	if grouped {
+		f.w.WriteString("(\n")
+	}
+	for _, spec := range decl.Specs {
+		switch spec := spec.(type) {
+		case *ast.TypeSpec:
+			f.writeDoc(spec.Doc)
+			f.w.WriteString(spec.Name.Name + " ")
+			err := f.writeExpr(spec.Type)
+			if err != nil {
+				return err
+			}
+		case *ast.ValueSpec:
+			f.writeDoc(spec.Doc)
+			for i, name := range spec.Names {
+				if i != 0 {
+					f.w.WriteString(", ")
+				}
+				f.w.WriteString(name.Name)
+			}
+			if spec.Type != nil {
+				f.w.WriteString(" ")
+				err := f.writeExpr(spec.Type)
+				if err != nil {
+					return err
+				}
+			}
+			for i, value := range spec.Values {
+				if i == 0 {
+					f.w.WriteString(" = ")
+				} else {
+					f.w.WriteString(", ")
+				}
+				err := f.writeExpr(value)
+				if err != nil {
+					return err
+				}
+			}
+		default:
+			err := format.Node(f.w, f.fileSet, spec)
+			if err != nil {
+				return err
+			}
+		}
+		f.w.WriteString("\n")
+	}
+	if grouped {
+		f.w.WriteString(")\n")
+	}
+	return nil
+}
+
+func specDoc(spec ast.Spec) *ast.CommentGroup {
+	switch spec := spec.(type) {
+	case *ast.TypeSpec:
+		return spec.Doc
+	case *ast.ValueSpec:
+		return spec.Doc
+	}
+	return nil
+}
+
+func (f *File) writeExpr(expr ast.Expr) error {
+	structType, ok := expr.(*ast.StructType)
+	if !ok {
+		return format.Node(f.w, f.fileSet, expr)
+	}
+	f.w.WriteString("struct {\n")
+	for _, field := range structType.Fields.List {
+		f.writeDoc(field.Doc)
+		for i, name := range field.Names {
+			if i != 0 {
+				f.w.WriteString(", ")
+			}
+			f.w.WriteString(name.Name + " ")
+		}
+		err := f.writeExpr(field.Type)
+		if err != nil {
+			return err
+		}
+		if field.Tag != nil {
+			f.w.WriteString(" " + field.Tag.Value)
+		}
+		f.w.WriteString("\n")
+	}
+	f.w.WriteString("}")
+	return nil
+}
+
+func (f *File) writeDoc(doc *ast.CommentGroup) {
+	if doc == nil {
+		return
+	}
+	for _, comment := range doc.List {
+		f.w.WriteString(comment.Text + "\n")
+	}
+}
+
+// sourceNode is Go source that is easier to write as text than to build up as an AST, such as
+// method bodies. It only works with File, not with go/printer.
+type sourceNode struct {
+	src string
+}
+
+func (*sourceNode) Pos() token.Pos { return token.NoPos }
+func (*sourceNode) End() token.Pos { return token.NoPos }
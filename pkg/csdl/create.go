@@ -0,0 +1,96 @@
+package csdl
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+func (p *PropType) requiredOnCreate() bool {
+	return p.RequiredOnCreate
+}
+
+// hasRequiredOnCreate returns true if the type has a property that must be set when the resource
+// is created, either directly or in a nested complex type
+func (t *Type) hasRequiredOnCreate(types map[string]*Type) bool {
+	return t.anyProperty(types, (*PropType).requiredOnCreate, map[*Type]bool{})
+}
+
+// createNodes generates the <Type>Create struct used as a POST body, along with a Validate method
+// that reports the required properties that are missing
+func (t *Type) createNodes(types map[string]*Type, props []propField) []ast.Node {
+	if t.Request || !t.hasRequiredOnCreate(types) {
+		return []ast.Node{}
+	}
+	name := t.GoTypeName() + "Create"
+	structType := &ast.StructType{
+		Fields: &ast.FieldList{},
+	}
+	body := &strings.Builder{}
+	for _, prop := range props {
+		prefix, base := prop.goType()
+		nested, isNested := prop.Prop.nestedStruct(t.Replacements, types)
+		nestedCreate := isNested && nested.hasRequiredOnCreate(types)
+		var goType string
+		switch {
+		case nestedCreate && prefix == "[]":
+			goType = "[]" + base + "Create"
+		case nestedCreate:
+			goType = "*" + base + "Create"
+		case (prop.Prop.RequiredOnCreate || prop.Prop.writable()) && (base == "any" || strings.HasPrefix(base, "map[")):
+			goType = base
+		case prop.Prop.RequiredOnCreate || prop.Prop.writable():
+			goType = "*" + strings.TrimPrefix(prefix, "*") + base
+		case isNested && nested.hasWritable(types) && prefix == "[]":
+			goType = "[]" + base + "Patch"
+		case isNested && nested.hasWritable(types):
+			goType = "*" + base + "Patch"
+		default:
+			continue
+		}
+		structType.Fields.List = append(structType.Fields.List, &ast.Field{
+			Doc:   prop.Field.Doc,
+			Names: []*ast.Ident{ast.NewIdent(prop.Name)},
+			Type:  &ast.Ident{Name: goType},
+			Tag:   jsonTag(prop.jsonName(), !prop.Prop.RequiredOnCreate),
+		})
+		field := "t." + prop.Name
+		path := "path+" + strconv.Quote("/"+prop.jsonName())
+		if prop.Prop.RequiredOnCreate {
+			body.WriteString("errs.checkRequired(" + path + ", " + field + " != nil)\n")
+		}
+		if nestedCreate && prefix == "[]" {
+			body.WriteString("for i := range " + field + " {\n")
+			body.WriteString(field + "[i].validate(jsonIndex(" + path + ", i), errs)\n")
+			body.WriteString("}\n")
+		} else if nestedCreate {
+			body.WriteString("if " + field + " != nil {\n")
+			body.WriteString(field + ".validate(" + path + ", errs)\n")
+			body.WriteString("}\n")
+		}
+	}
+	return []ast.Node{
+		&ast.GenDecl{
+			Doc: docComment(name + " is the POST body used to create a " + t.GoTypeName() + ", the fields without omitempty are required on create."),
+			Tok: token.TYPE,
+			Specs: []ast.Spec{
+				&ast.TypeSpec{
+					Name: ast.NewIdent(name),
+					Type: structType,
+				},
+			},
+		},
+		&sourceNode{src: `
+// Validate returns a ValidationErrors listing the properties that are required on create but not set
+func (t *` + name + `) Validate() error {
+	errs := ValidationErrors{}
+	t.validate("", &errs)
+	return errs.Err()
+}
+
+func (t *` + name + `) validate(path string, errs *ValidationErrors) {
+` + body.String() + `}
+`},
+	}
+}
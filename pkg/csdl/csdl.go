@@ -1,5 +1,7 @@
 package csdl
 
+import "encoding/xml"
+
 type Edmx struct {
 	Reference    []Reference  `xml:"Reference"`
 	DataServices DataServices `xml:"DataServices"`
@@ -43,16 +45,23 @@ type Action struct {
 	Annotation []Annotation `xml:"Annotation"`
 	Parameter  []Parameter  `xml:"Parameter"`
 	Other      []any        `xml:",any"`
+	Line       int          `xml:"-"` // where the element starts, for diagnostics
 }
 
 type Annotation struct {
-	Term       string  `xml:"Term,attr"`
-	Qualifier  string  `xml:"Qualifier,attr"`
-	String     string  `xml:"String,attr"`
-	EnumMember string  `xml:"EnumMember,attr"`
-	Bool       bool    `xml:"Bool,attr"`
-	Int        int64   `xml:"Int,attr"`
-	Decimal    float64 `xml:"Decimal,attr"`
+	Term      string
+	Qualifier string
+	// String, EnumMember, Bool, Int and Decimal are set when the value is a constant of that kind,
+	// like the attributes of the XML form. Expr has the value whatever it is.
+	String     string
+	EnumMember string
+	Bool       bool
+	Int        int64
+	Decimal    float64
+	// Expr is the value, nil if there isn't one which for tag terms like Redfish.Required means true
+	Expr Expr
+	// Annotation are the annotations of the annotation
+	Annotation []Annotation
 }
 
 type Annotations struct {
@@ -69,10 +78,35 @@ type ComplexType struct {
 	Property           []Property           `xml:"Property"`
 	NavigationProperty []NavigationProperty `xml:"NavigationProperty"`
 	Annotation         []Annotation         `xml:"Annotation"`
+	Line               int                  `xml:"-"` // where the element starts, for diagnostics
 }
 
 type EntityContainer struct {
-	Other []any `xml:",any"`
+	Name       string       `xml:"Name,attr"`
+	Extends    string       `xml:"Extends,attr"`
+	EntitySet  []EntitySet  `xml:"EntitySet"`
+	Singleton  []Singleton  `xml:"Singleton"`
+	Annotation []Annotation `xml:"Annotation"`
+	Other      []any        `xml:",any"`
+}
+
+type EntitySet struct {
+	Name                      string                      `xml:"Name,attr"`
+	EntityType                string                      `xml:"EntityType,attr"`
+	NavigationPropertyBinding []NavigationPropertyBinding `xml:"NavigationPropertyBinding"`
+	Annotation                []Annotation                `xml:"Annotation"`
+}
+
+type Singleton struct {
+	Name                      string                      `xml:"Name,attr"`
+	Type                      string                      `xml:"Type,attr"`
+	NavigationPropertyBinding []NavigationPropertyBinding `xml:"NavigationPropertyBinding"`
+	Annotation                []Annotation                `xml:"Annotation"`
+}
+
+type NavigationPropertyBinding struct {
+	Path   string `xml:"Path,attr"`
+	Target string `xml:"Target,attr"`
 }
 
 type EntityType struct {
@@ -84,6 +118,8 @@ type EntityType struct {
 	Key                Key                  `xml:"Key"`
 	Property           []Property           `xml:"Property"`
 	NavigationProperty []NavigationProperty `xml:"NavigationProperty"`
+	Annotation         []Annotation         `xml:"Annotation"`
+	Line               int                  `xml:"-"` // where the element starts, for diagnostics
 }
 
 type EnumType struct {
@@ -92,6 +128,7 @@ type EnumType struct {
 	IsFlags        bool   `xml:"IsFlags,attr"`
 	Annotation     []Annotation
 	Member         []Member
+	Line           int `xml:"-"` // where the element starts, for diagnostics
 }
 
 type Function struct {
@@ -114,10 +151,15 @@ type NavigationProperty struct {
 	Partner        string       `xml:"Partner,attr"`
 	ContainsTarget bool         `xml:"ContainsTarget,attr"`
 	Annotation     []Annotation `xml:"Annotation"`
+	Line           int          `xml:"-"` // where the element starts, for diagnostics
 }
 
 type Parameter struct {
-	Other []any `xml:",any"`
+	Name       string       `xml:"Name,attr"`
+	Type       string       `xml:"Type,attr"`
+	Nullable   *bool        `xml:"Nullable,attr"`
+	Annotation []Annotation `xml:"Annotation"`
+	Line       int          `xml:"-"` // where the element starts, for diagnostics
 }
 
 type Property struct {
@@ -131,14 +173,70 @@ type Property struct {
 	SRID         string       `xml:"SRID,attr"`
 	DefaultValue string       `xml:"DefaultValue,attr"`
 	Annotation   []Annotation `xml:"Annotation"`
+	Line         int          `xml:"-"` // where the element starts, for diagnostics
 }
 
 type Term struct {
-	Other []any `xml:",any"`
+	Name  string `xml:"Name,attr"`
+	Type  string `xml:"Type,attr"`
+	Other []any  `xml:",any"`
 }
 
 type TypeDefinition struct {
 	Name           string       `xml:"Name,attr"`
 	UnderlyingType string       `xml:"UnderlyingType,attr"`
 	Annotation     []Annotation `xml:"Annotation"`
+	Line           int          `xml:"-"` // where the element starts, for diagnostics
+}
+
+// The UnmarshalXML methods record the line each element is on so problems can be reported against the
+// document. The decoder's position is the end of the start element, which is the line of the start
+// tag unless the attributes are spread over several lines.
+
+func (a *Action) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type plain Action
+	a.Line, _ = d.InputPos()
+	return d.DecodeElement((*plain)(a), &start)
+}
+
+func (c *ComplexType) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type plain ComplexType
+	c.Line, _ = d.InputPos()
+	return d.DecodeElement((*plain)(c), &start)
+}
+
+func (e *EntityType) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type plain EntityType
+	e.Line, _ = d.InputPos()
+	return d.DecodeElement((*plain)(e), &start)
+}
+
+func (e *EnumType) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type plain EnumType
+	e.Line, _ = d.InputPos()
+	return d.DecodeElement((*plain)(e), &start)
+}
+
+func (n *NavigationProperty) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type plain NavigationProperty
+	n.Line, _ = d.InputPos()
+	return d.DecodeElement((*plain)(n), &start)
+}
+
+func (p *Parameter) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type plain Parameter
+	p.Line, _ = d.InputPos()
+	return d.DecodeElement((*plain)(p), &start)
+}
+
+func (p *Property) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type plain Property
+	p.Line, _ = d.InputPos()
+	return d.DecodeElement((*plain)(p), &start)
+}
+
+func (t *TypeDefinition) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type plain TypeDefinition
+	t.Line, _ = d.InputPos()
+	return d.DecodeElement((*plain)(t), &start)
 }
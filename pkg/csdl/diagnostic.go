@@ -0,0 +1,136 @@
+package csdl
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+)
+
+type Severity int
+
+const (
+	// Warning is a problem the generator worked around, i.e. by using any for a type it couldn't find
+	Warning Severity = iota
+	// Error is a problem that means the generated code is wrong or missing something
+	Error
+)
+
+func (s Severity) String() string {
+	if s == Error {
+		return "error"
+	}
+	return "warning"
+}
+
+// Diagnostic is a problem found in the CSDL
+type Diagnostic struct {
+	Severity Severity
+	File     string
+	Line     int    // 0 if the line isn't known, i.e. for JSON CSDL or types the generator made up
+	Name     string // qualified name of what the problem is with, properties are Type/Property
+	Message  string
+}
+
+func (d Diagnostic) String() string {
+	position := d.File
+	if d.Line != 0 {
+		position = fmt.Sprintf("%s:%d", d.File, d.Line)
+	}
+	return fmt.Sprintf("%s: %s: %s: %s", position, d.Severity, d.Name, d.Message)
+}
+
+type Diagnostics []Diagnostic
+
+func (d *Diagnostics) add(severity Severity, file string, line int, name string, format string, args ...any) {
+	*d = append(*d, Diagnostic{
+		Severity: severity,
+		File:     file,
+		Line:     line,
+		Name:     name,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// Sort orders the diagnostics by file and line
+func (d Diagnostics) Sort() {
+	slices.SortStableFunc(d, func(a, b Diagnostic) int {
+		return cmp.Or(cmp.Compare(a.File, b.File), cmp.Compare(a.Line, b.Line), cmp.Compare(a.Name, b.Name))
+	})
+}
+
+// Fatal returns true if there are any errors, or any warnings if warningsAreErrors is set
+func (d Diagnostics) Fatal(warningsAreErrors bool) bool {
+	return slices.ContainsFunc(d, func(diagnostic Diagnostic) bool {
+		return diagnostic.Severity == Error || warningsAreErrors
+	})
+}
+
+// Check reports the problems with folded types that the code generator works around or can't
+// generate code for
+func Check(types map[string]*Type) Diagnostics {
+	diagnostics := Diagnostics{}
+	// Inherited properties are only reported once, against the first type they show up in
+	seen := map[string]bool{}
+	for _, name := range SortedNames(types) {
+		t := types[name]
+		if len(t.Members) != 0 {
+			diagnostics = append(diagnostics, t.checkEnum(name)...)
+		}
+		if !t.isStruct() && len(t.Members) == 0 {
+			_, ok := t.newestVersion(types)
+			if !ok && !t.skipped() && !t.Abstract {
+				diagnostics.add(Error, t.File, t.Line, name, "has no properties, members, or versioned definition, nothing is generated for it")
+			}
+			continue
+		}
+		for _, propName := range slices.Sorted(maps.Keys(t.Properties)) {
+			if propName == "Actions" || propName == "Oem" {
+				// These aren't generated from the property type
+				continue
+			}
+			prop := t.Properties[propName]
+			key := fmt.Sprintf("%s:%d:%s", prop.File, prop.Line, propName)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			replacement, ok := t.Replacements[prop.Type]
+			if ok {
+				prop.Type = replacement
+			}
+			_, ok = prop.expr(types)
+			if !ok {
+				diagnostics.add(Warning, prop.File, prop.Line, name+"/"+propName, "unknown type %s, the field is generated as any", prop.Type)
+			}
+			field := propField{Name: propName, Prop: prop, Field: prop.field(propName, types, t.Replacements)}
+			if prop.Required && !field.requiredCheck() {
+				diagnostics.add(Warning, prop.File, prop.Line, name+"/"+propName, "Redfish.Required isn't checked by Validate, the field can't be nil so a missing property can't be told apart from the zero value")
+			}
+			if prop.Pattern != "" {
+				_, err := regexp.Compile(prop.Pattern)
+				if err != nil {
+					diagnostics.add(Warning, prop.File, prop.Line, name+"/"+propName, "the pattern isn't validated, Go can't compile it: %s", err)
+				}
+			}
+		}
+	}
+	return diagnostics
+}
+
+// checkEnum reports the underlying types and member values integer enums can't use
+func (t *Type) checkEnum(name string) Diagnostics {
+	diagnostics := Diagnostics{}
+	if t.UnderlyingType != "" && enumGoTypes[t.UnderlyingType] == "" {
+		diagnostics.add(Warning, t.File, t.Line, name, "underlying type %s isn't an integer type, Edm.Int32 is used", t.UnderlyingType)
+	}
+	for _, member := range t.Members {
+		_, err := strconv.ParseInt(member.Value, 10, 64)
+		if member.Value != "" && err != nil {
+			diagnostics.add(Warning, t.File, t.Line, name+"/"+member.Name, "value %s isn't an integer, the member's position is used", member.Value)
+		}
+	}
+	return diagnostics
+}
@@ -0,0 +1,454 @@
+package csdl
+
+import (
+	"cmp"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ErrNotConstant is returned by Evaluate for expressions whose value depends on the instance being
+// annotated or on something outside of the expression, like Path or UrlRef
+var ErrNotConstant = errors.New("expression isn't constant")
+
+// EnumValue is the value of an EnumMember expression, the qualified members like
+// "Redfish.RevisionKind/Added", there is more than one for a flags enum
+type EnumValue []string
+
+// Evaluate returns the value of a constant expression as a Go value:
+//   - String, Guid and Duration are a string
+//   - Bool is a bool, Int is an int64 and Decimal and Float are a float64
+//   - Date, DateTimeOffset and TimeOfDay are a time.Time
+//   - Binary is a []byte
+//   - EnumMember is an EnumValue
+//   - Null, and a missing expression, are nil
+//   - Collection is a []any and Record is a map[string]any of the property values
+//
+// The operators, If, Cast, IsOf, LabeledElement and the odata.concat function are evaluated from
+// their operands. Paths, UrlRef, LabeledElementReference and the other functions return an error
+// wrapping ErrNotConstant.
+func Evaluate(expr Expr) (any, error) {
+	switch expr := expr.(type) {
+	case nil, Null:
+		return nil, nil
+	case Constant:
+		return evaluateConstant(expr)
+	case PathExpr:
+		return nil, fmt.Errorf("%w: %s %s", ErrNotConstant, expr.Kind, expr.Path)
+	case LabeledElementReference:
+		return nil, fmt.Errorf("%w: LabeledElementReference %s", ErrNotConstant, expr.Name)
+	case UrlRef:
+		return nil, fmt.Errorf("%w: UrlRef", ErrNotConstant)
+	case Collection:
+		values := make([]any, 0, len(expr.Items))
+		for _, item := range expr.Items {
+			value, err := Evaluate(item)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return values, nil
+	case Record:
+		values := make(map[string]any, len(expr.PropertyValue))
+		for _, propertyValue := range expr.PropertyValue {
+			value, err := Evaluate(propertyValue.Value)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", propertyValue.Property, err)
+			}
+			values[propertyValue.Property] = value
+		}
+		return values, nil
+	case LabeledElement:
+		return Evaluate(expr.Value)
+	case If:
+		condition, err := evaluateBool(expr.Condition)
+		if err != nil {
+			return nil, fmt.Errorf("If: %w", err)
+		}
+		if condition {
+			return Evaluate(expr.Then)
+		}
+		return Evaluate(expr.Else)
+	case Cast:
+		value, err := Evaluate(expr.Value)
+		if err != nil {
+			return nil, err
+		}
+		if expr.IsOf {
+			return isOf(value, expr.Type), nil
+		}
+		return cast(value, expr.Type), nil
+	case Apply:
+		return apply(expr)
+	case UnaryExpr:
+		return evaluateUnary(expr)
+	case BinaryExpr:
+		return evaluateBinary(expr)
+	}
+	return nil, fmt.Errorf("unknown expression %T", expr)
+}
+
+func evaluateConstant(c Constant) (any, error) {
+	text := strings.TrimSpace(c.Value)
+	var value any
+	var err error
+	switch c.Kind {
+	case StringKind:
+		// Whitespace is part of a string
+		return c.Value, nil
+	case GuidKind, DurationKind:
+		return text, nil
+	case BoolKind:
+		value, err = strconv.ParseBool(text)
+	case IntKind:
+		value, err = strconv.ParseInt(text, 10, 64)
+	case DecimalKind, FloatKind:
+		value, err = strconv.ParseFloat(text, 64)
+	case DateKind:
+		value, err = time.Parse(time.DateOnly, text)
+	case DateTimeOffsetKind:
+		value, err = time.Parse(time.RFC3339Nano, text)
+	case TimeOfDayKind:
+		value, err = time.Parse("15:04:05.999999999", text)
+		if err != nil {
+			// Seconds are optional
+			value, err = time.Parse("15:04", text)
+		}
+	case BinaryKind:
+		// Binary is base64url, padding is optional
+		value, err = base64.RawURLEncoding.DecodeString(strings.TrimRight(text, "="))
+	case EnumMemberKind:
+		return EnumValue(strings.FieldsFunc(text, isEnumSeparator)), nil
+	default:
+		return nil, fmt.Errorf("unknown constant %s", c.Kind)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q", c.Kind, c.Value)
+	}
+	return value, nil
+}
+
+// isEnumSeparator separates the members of a flags enum value, XML uses spaces and JSON commas
+func isEnumSeparator(r rune) bool {
+	return r == ',' || unicode.IsSpace(r)
+}
+
+func evaluateBool(expr Expr) (bool, error) {
+	value, err := Evaluate(expr)
+	if err != nil {
+		return false, err
+	}
+	b, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("expected a Bool, got %v", value)
+	}
+	return b, nil
+}
+
+func evaluateUnary(expr UnaryExpr) (any, error) {
+	value, err := Evaluate(expr.Operand)
+	if err != nil {
+		return nil, err
+	}
+	switch value := value.(type) {
+	case bool:
+		if expr.Operator == "Not" {
+			return !value, nil
+		}
+	case int64:
+		if expr.Operator == "Neg" {
+			return -value, nil
+		}
+	case float64:
+		if expr.Operator == "Neg" {
+			return -value, nil
+		}
+	}
+	return nil, fmt.Errorf("%s: invalid operand %v", expr.Operator, value)
+}
+
+func evaluateBinary(expr BinaryExpr) (any, error) {
+	left, err := Evaluate(expr.Left)
+	if err != nil {
+		return nil, err
+	}
+	// And and Or short circuit
+	if b, ok := left.(bool); ok && ((expr.Operator == "And" && !b) || (expr.Operator == "Or" && b)) {
+		return b, nil
+	}
+	right, err := Evaluate(expr.Right)
+	if err != nil {
+		return nil, err
+	}
+	invalid := fmt.Errorf("%s: invalid operands %v and %v", expr.Operator, left, right)
+	switch expr.Operator {
+	case "And", "Or":
+		b, ok := right.(bool)
+		if _, leftOk := left.(bool); !ok || !leftOk {
+			return nil, invalid
+		}
+		return b, nil
+	case "Eq":
+		return equal(left, right), nil
+	case "Ne":
+		return !equal(left, right), nil
+	case "Gt", "Ge", "Lt", "Le":
+		order, ok := compare(left, right)
+		if !ok {
+			return nil, invalid
+		}
+		switch expr.Operator {
+		case "Gt":
+			return order > 0, nil
+		case "Ge":
+			return order >= 0, nil
+		case "Lt":
+			return order < 0, nil
+		}
+		return order <= 0, nil
+	case "Has":
+		flags, ok := left.(EnumValue)
+		members, rightOk := right.(EnumValue)
+		if !ok || !rightOk {
+			return nil, invalid
+		}
+		for _, member := range members {
+			if !slices.Contains(flags, member) {
+				return false, nil
+			}
+		}
+		return true, nil
+	case "In":
+		values, ok := right.([]any)
+		if !ok {
+			return nil, invalid
+		}
+		return slices.ContainsFunc(values, func(value any) bool { return equal(left, value) }), nil
+	}
+	value, ok := arithmetic(expr.Operator, left, right)
+	if !ok {
+		return nil, invalid
+	}
+	return value, nil
+}
+
+// arithmetic applies Add, Sub, Mul, Div, DivBy or Mod. Two Ints stay an Int, except for DivBy which
+// is always a Decimal, and division by zero is invalid.
+func arithmetic(operator string, left any, right any) (any, bool) {
+	l, lInt := left.(int64)
+	r, rInt := right.(int64)
+	if lInt && rInt && operator != "DivBy" {
+		switch operator {
+		case "Add":
+			return l + r, true
+		case "Sub":
+			return l - r, true
+		case "Mul":
+			return l * r, true
+		case "Div", "Mod":
+			if r == 0 {
+				return nil, false
+			}
+			if operator == "Div" {
+				return l / r, true
+			}
+			return l % r, true
+		}
+		return nil, false
+	}
+	lf, ok := toFloat(left)
+	rf, rightOk := toFloat(right)
+	if !ok || !rightOk {
+		return nil, false
+	}
+	switch operator {
+	case "Add":
+		return lf + rf, true
+	case "Sub":
+		return lf - rf, true
+	case "Mul":
+		return lf * rf, true
+	case "Div", "DivBy", "Mod":
+		if rf == 0 {
+			return nil, false
+		}
+		if operator == "Mod" {
+			return math.Mod(lf, rf), true
+		}
+		return lf / rf, true
+	}
+	return nil, false
+}
+
+func toFloat(value any) (float64, bool) {
+	switch value := value.(type) {
+	case int64:
+		return float64(value), true
+	case float64:
+		return value, true
+	}
+	return 0, false
+}
+
+// equal compares values of the same kind, Ints and Decimals are compared as numbers
+func equal(left any, right any) bool {
+	order, ok := compare(left, right)
+	if ok {
+		return order == 0
+	}
+	switch l := left.(type) {
+	case nil:
+		return right == nil
+	case bool:
+		r, ok := right.(bool)
+		return ok && l == r
+	case EnumValue:
+		r, ok := right.(EnumValue)
+		return ok && slices.Equal(l, r)
+	case []byte:
+		r, ok := right.([]byte)
+		return ok && slices.Equal(l, r)
+	}
+	return false
+}
+
+// compare orders numbers, strings and times, false if they can't be ordered
+func compare(left any, right any) (int, bool) {
+	l, lInt := left.(int64)
+	r, rInt := right.(int64)
+	if lInt && rInt {
+		return cmp.Compare(l, r), true
+	}
+	lf, ok := toFloat(left)
+	rf, rightOk := toFloat(right)
+	if ok && rightOk {
+		return cmp.Compare(lf, rf), true
+	}
+	switch l := left.(type) {
+	case string:
+		r, ok := right.(string)
+		return strings.Compare(l, r), ok
+	case time.Time:
+		r, ok := right.(time.Time)
+		return l.Compare(r), ok
+	}
+	return 0, false
+}
+
+// edmIntRanges are the ranges of the integer types for IsOf and Cast
+var edmIntRanges = map[string][2]int64{
+	"Edm.Byte":  {0, math.MaxUint8},
+	"Edm.SByte": {math.MinInt8, math.MaxInt8},
+	"Edm.Int16": {math.MinInt16, math.MaxInt16},
+	"Edm.Int32": {math.MinInt32, math.MaxInt32},
+	"Edm.Int64": {math.MinInt64, math.MaxInt64},
+}
+
+// isOf returns true if the value is an instance of the primitive type
+func isOf(value any, typeName string) bool {
+	switch value := value.(type) {
+	case string:
+		return typeName == "Edm.String"
+	case bool:
+		return typeName == "Edm.Boolean"
+	case int64:
+		limits, ok := edmIntRanges[typeName]
+		return ok && value >= limits[0] && value <= limits[1]
+	case float64:
+		return typeName == "Edm.Decimal" || typeName == "Edm.Double" || typeName == "Edm.Single"
+	case []byte:
+		return typeName == "Edm.Binary"
+	}
+	return false
+}
+
+// cast converts the value to the primitive type, the result is nil if it can't be
+func cast(value any, typeName string) any {
+	if isOf(value, typeName) {
+		return value
+	}
+	switch typeName {
+	case "Edm.String":
+		switch value := value.(type) {
+		case int64:
+			return strconv.FormatInt(value, 10)
+		case float64:
+			return strconv.FormatFloat(value, 'g', -1, 64)
+		case bool:
+			return strconv.FormatBool(value)
+		}
+	case "Edm.Boolean":
+		s, ok := value.(string)
+		if ok {
+			b, err := strconv.ParseBool(s)
+			if err == nil {
+				return b
+			}
+		}
+	case "Edm.Decimal", "Edm.Double", "Edm.Single":
+		f, ok := toFloat(value)
+		if ok {
+			return f
+		}
+		s, ok := value.(string)
+		if ok {
+			f, err := strconv.ParseFloat(s, 64)
+			if err == nil {
+				return f
+			}
+		}
+	default:
+		limits, ok := edmIntRanges[typeName]
+		if !ok {
+			return nil
+		}
+		var i int64
+		switch value := value.(type) {
+		case float64:
+			if value != math.Trunc(value) || value < math.MinInt64 || value >= math.MaxInt64 {
+				return nil
+			}
+			i = int64(value)
+		case string:
+			var err error
+			i, err = strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil
+			}
+		default:
+			return nil
+		}
+		if i >= limits[0] && i <= limits[1] {
+			return i
+		}
+	}
+	return nil
+}
+
+// apply evaluates the client side functions that don't need a model, which is odata.concat
+func apply(expr Apply) (any, error) {
+	if expr.Function != "odata.concat" {
+		return nil, fmt.Errorf("%w: function %s", ErrNotConstant, expr.Function)
+	}
+	buf := strings.Builder{}
+	for _, arg := range expr.Args {
+		value, err := Evaluate(arg)
+		if err != nil {
+			return nil, err
+		}
+		value = cast(value, "Edm.String")
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("odata.concat: invalid argument %v", arg)
+		}
+		buf.WriteString(s)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,346 @@
+package csdl
+
+import (
+	"encoding/xml"
+	"slices"
+	"strings"
+)
+
+// Expr is an annotation expression, see the Annotations section of the CSDL XML specification. The
+// element and attribute forms of an expression decode to the same node.
+type Expr interface {
+	expr()
+}
+
+// Kinds of constant expressions, the names of the elements and attributes
+const (
+	BinaryKind         = "Binary"
+	BoolKind           = "Bool"
+	DateKind           = "Date"
+	DateTimeOffsetKind = "DateTimeOffset"
+	DecimalKind        = "Decimal"
+	DurationKind       = "Duration"
+	EnumMemberKind     = "EnumMember"
+	FloatKind          = "Float"
+	GuidKind           = "Guid"
+	IntKind            = "Int"
+	StringKind         = "String"
+	TimeOfDayKind      = "TimeOfDay"
+)
+
+// Kinds of path expressions
+const (
+	AnnotationPathKind         = "AnnotationPath"
+	ModelElementPathKind       = "ModelElementPath"
+	NavigationPropertyPathKind = "NavigationPropertyPath"
+	PathKind                   = "Path"
+	PropertyPathKind           = "PropertyPath"
+)
+
+var (
+	constantKinds = []string{BinaryKind, BoolKind, DateKind, DateTimeOffsetKind, DecimalKind, DurationKind, EnumMemberKind, FloatKind, GuidKind, IntKind, StringKind, TimeOfDayKind}
+	pathKinds     = []string{AnnotationPathKind, ModelElementPathKind, NavigationPropertyPathKind, PathKind, PropertyPathKind}
+	// binaryOperators take two operands, the comparison and logical operators are from 4.0 and the
+	// arithmetic operators from 4.01
+	binaryOperators = []string{"And", "Or", "Eq", "Ne", "Gt", "Ge", "Lt", "Le", "Has", "In", "Add", "Sub", "Mul", "Div", "DivBy", "Mod"}
+	unaryOperators  = []string{"Not", "Neg"}
+)
+
+// Constant is a constant expression, Value is the text of the literal, i.e. "true" for a Bool or
+// "Redfish.RevisionKind/Added" for an EnumMember
+type Constant struct {
+	Kind  string
+	Value string
+}
+
+// PathExpr is one of the path expressions, Kind is which one
+type PathExpr struct {
+	Kind string
+	Path string
+}
+
+type Null struct {
+	Annotation []Annotation
+}
+
+type Collection struct {
+	Items []Expr
+}
+
+type Record struct {
+	Type          string
+	PropertyValue []PropertyValue
+	Annotation    []Annotation
+}
+
+type PropertyValue struct {
+	Property   string
+	Value      Expr
+	Annotation []Annotation
+}
+
+// Apply is a client side function, i.e. odata.concat
+type Apply struct {
+	Function   string
+	Args       []Expr
+	Annotation []Annotation
+}
+
+// Cast is both the Cast and the IsOf expression, IsOf is true for the latter
+type Cast struct {
+	IsOf       bool
+	Type       string
+	Value      Expr
+	Annotation []Annotation
+}
+
+type If struct {
+	Condition  Expr
+	Then       Expr
+	Else       Expr
+	Annotation []Annotation
+}
+
+// BinaryExpr is a comparison, logical or arithmetic operator with two operands, Operator is the
+// element name like Eq or And
+type BinaryExpr struct {
+	Operator   string
+	Left       Expr
+	Right      Expr
+	Annotation []Annotation
+}
+
+// UnaryExpr is Not or Neg
+type UnaryExpr struct {
+	Operator   string
+	Operand    Expr
+	Annotation []Annotation
+}
+
+type LabeledElement struct {
+	Name       string
+	Value      Expr
+	Annotation []Annotation
+}
+
+type LabeledElementReference struct {
+	Name string
+}
+
+type UrlRef struct {
+	Value      Expr
+	Annotation []Annotation
+}
+
+func (Constant) expr()                {}
+func (PathExpr) expr()                {}
+func (Null) expr()                    {}
+func (Collection) expr()              {}
+func (Record) expr()                  {}
+func (Apply) expr()                   {}
+func (Cast) expr()                    {}
+func (If) expr()                      {}
+func (BinaryExpr) expr()              {}
+func (UnaryExpr) expr()               {}
+func (LabeledElement) expr()          {}
+func (LabeledElementReference) expr() {}
+func (UrlRef) expr()                  {}
+
+// attrExpr returns the expression for the attribute form of a constant or path expression
+func attrExpr(attr xml.Attr) (Expr, bool) {
+	if slices.Contains(constantKinds, attr.Name.Local) {
+		return Constant{Kind: attr.Name.Local, Value: attr.Value}, true
+	}
+	if slices.Contains(pathKinds, attr.Name.Local) {
+		return PathExpr{Kind: attr.Name.Local, Path: attr.Value}, true
+	}
+	if attr.Name.Local == "UrlRef" {
+		return UrlRef{Value: Constant{Kind: StringKind, Value: attr.Value}}, true
+	}
+	return nil, false
+}
+
+// xmlChildren are the child elements of an expression, annotation or property value
+type xmlChildren struct {
+	exprs          []Expr
+	annotations    []Annotation
+	propertyValues []PropertyValue
+}
+
+// first returns the first child expression, or the attribute form if there isn't one
+func (c xmlChildren) first(attr Expr) Expr {
+	if len(c.exprs) != 0 {
+		return c.exprs[0]
+	}
+	return attr
+}
+
+// at returns the i'th child expression, or nil if the element is missing it
+func (c xmlChildren) at(i int) Expr {
+	if i < len(c.exprs) {
+		return c.exprs[i]
+	}
+	return nil
+}
+
+// decodeXMLChildren reads the child elements up to the end of the current element in document
+// order, unknown elements are skipped
+func decodeXMLChildren(d *xml.Decoder) (xmlChildren, error) {
+	children := xmlChildren{}
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return children, err
+		}
+		switch tok := tok.(type) {
+		case xml.EndElement:
+			return children, nil
+		case xml.StartElement:
+			switch tok.Name.Local {
+			case "Annotation":
+				annotation := Annotation{}
+				err = d.DecodeElement(&annotation, &tok)
+				children.annotations = append(children.annotations, annotation)
+			case "PropertyValue":
+				propertyValue := PropertyValue{}
+				err = d.DecodeElement(&propertyValue, &tok)
+				children.propertyValues = append(children.propertyValues, propertyValue)
+			default:
+				var expr Expr
+				expr, err = decodeXMLExpr(d, tok)
+				if expr != nil {
+					children.exprs = append(children.exprs, expr)
+				}
+			}
+			if err != nil {
+				return children, err
+			}
+		}
+	}
+}
+
+// decodeXMLExpr decodes the element form of an expression, nil for elements that aren't one
+func decodeXMLExpr(d *xml.Decoder, start xml.StartElement) (Expr, error) {
+	name := start.Name.Local
+	if slices.Contains(constantKinds, name) || slices.Contains(pathKinds, name) || name == "LabeledElementReference" {
+		text := ""
+		err := d.DecodeElement(&text, &start)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case slices.Contains(constantKinds, name):
+			return Constant{Kind: name, Value: text}, nil
+		case slices.Contains(pathKinds, name):
+			return PathExpr{Kind: name, Path: strings.TrimSpace(text)}, nil
+		}
+		return LabeledElementReference{Name: strings.TrimSpace(text)}, nil
+	}
+	attrs := map[string]string{}
+	var attrForm Expr
+	for _, attr := range start.Attr {
+		attrs[attr.Name.Local] = attr.Value
+		expr, ok := attrExpr(attr)
+		if ok {
+			attrForm = expr
+		}
+	}
+	switch name {
+	case "Null", "Collection", "Record", "Apply", "Cast", "IsOf", "If", "LabeledElement", "UrlRef":
+	default:
+		if !slices.Contains(binaryOperators, name) && !slices.Contains(unaryOperators, name) {
+			return nil, d.Skip()
+		}
+	}
+	children, err := decodeXMLChildren(d)
+	if err != nil {
+		return nil, err
+	}
+	switch name {
+	case "Null":
+		return Null{Annotation: children.annotations}, nil
+	case "Collection":
+		return Collection{Items: children.exprs}, nil
+	case "Record":
+		return Record{Type: attrs["Type"], PropertyValue: children.propertyValues, Annotation: children.annotations}, nil
+	case "Apply":
+		return Apply{Function: attrs["Function"], Args: children.exprs, Annotation: children.annotations}, nil
+	case "Cast", "IsOf":
+		return Cast{IsOf: name == "IsOf", Type: attrs["Type"], Value: children.first(nil), Annotation: children.annotations}, nil
+	case "If":
+		return If{Condition: children.at(0), Then: children.at(1), Else: children.at(2), Annotation: children.annotations}, nil
+	case "LabeledElement":
+		return LabeledElement{Name: attrs["Name"], Value: children.first(attrForm), Annotation: children.annotations}, nil
+	case "UrlRef":
+		return UrlRef{Value: children.first(nil), Annotation: children.annotations}, nil
+	case "Not", "Neg":
+		return UnaryExpr{Operator: name, Operand: children.at(0), Annotation: children.annotations}, nil
+	}
+	return BinaryExpr{Operator: name, Left: children.at(0), Right: children.at(1), Annotation: children.annotations}, nil
+}
+
+func (a *Annotation) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var attrForm Expr
+	for _, attr := range start.Attr {
+		switch attr.Name.Local {
+		case "Term":
+			a.Term = attr.Value
+		case "Qualifier":
+			a.Qualifier = attr.Value
+		default:
+			expr, ok := attrExpr(attr)
+			if ok {
+				attrForm = expr
+			}
+		}
+	}
+	children, err := decodeXMLChildren(d)
+	if err != nil {
+		return err
+	}
+	a.Expr = children.first(attrForm)
+	a.Annotation = children.annotations
+	a.setConstant()
+	return nil
+}
+
+// setConstant sets the String, EnumMember, Bool, Int and Decimal fields from Expr
+func (a *Annotation) setConstant() {
+	a.String, a.EnumMember, a.Bool, a.Int, a.Decimal = "", "", false, 0, 0
+	constant, ok := a.Expr.(Constant)
+	if !ok {
+		return
+	}
+	switch constant.Kind {
+	case StringKind:
+		a.String = constant.Value
+	case EnumMemberKind:
+		a.EnumMember = constant.Value
+	case BoolKind, IntKind, DecimalKind, FloatKind:
+		value, _ := evaluateConstant(constant)
+		a.Bool, _ = value.(bool)
+		a.Int, _ = value.(int64)
+		a.Decimal, _ = value.(float64)
+	}
+}
+
+func (p *PropertyValue) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var attrForm Expr
+	for _, attr := range start.Attr {
+		if attr.Name.Local == "Property" {
+			p.Property = attr.Value
+			continue
+		}
+		expr, ok := attrExpr(attr)
+		if ok {
+			attrForm = expr
+		}
+	}
+	children, err := decodeXMLChildren(d)
+	if err != nil {
+		return err
+	}
+	p.Value = children.first(attrForm)
+	p.Annotation = children.annotations
+	return nil
+}
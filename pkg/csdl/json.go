@@ -0,0 +1,729 @@
+package csdl
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// jsonMember is one member of a JSON object, CSDL JSON relies on member order for things like
+// enum members so objects can't be decoded into maps
+type jsonMember struct {
+	Key   string
+	Value json.RawMessage
+}
+
+// decodeJSONObject returns the members of a JSON object in document order
+func decodeJSONObject(data []byte) ([]jsonMember, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if tok != json.Delim('{') {
+		return nil, fmt.Errorf("expected a JSON object, got %v", tok)
+	}
+	members := []jsonMember{}
+	for dec.More() {
+		tok, err = dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected a JSON object key, got %v", tok)
+		}
+		var value json.RawMessage
+		err = dec.Decode(&value)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, jsonMember{Key: key, Value: value})
+	}
+	return members, nil
+}
+
+// jsonFacets are the members that CSDL JSON uses for properties, parameters and type definitions
+type jsonFacets struct {
+	Kind           string  `json:"$Kind"`
+	Type           *string `json:"$Type"`
+	Collection     bool    `json:"$Collection"`
+	Nullable       bool    `json:"$Nullable"`
+	MaxLength      int     `json:"$MaxLength"`
+	Unicode        *bool   `json:"$Unicode"`
+	Precision      int     `json:"$Precision"`
+	Scale          any     `json:"$Scale"`
+	SRID           any     `json:"$SRID"`
+	DefaultValue   any     `json:"$DefaultValue"`
+	Partner        string  `json:"$Partner"`
+	ContainsTarget bool    `json:"$ContainsTarget"`
+	BaseType       string  `json:"$BaseType"`
+	Abstract       bool    `json:"$Abstract"`
+	OpenType       bool    `json:"$OpenType"`
+	HasStream      bool    `json:"$HasStream"`
+	UnderlyingType string  `json:"$UnderlyingType"`
+	IsFlags        bool    `json:"$IsFlags"`
+	IsBound        bool    `json:"$IsBound"`
+	Name           string  `json:"$Name"`
+}
+
+// typeName returns the qualified type name in the form the XML uses, i.e. "Collection(Edm.String)".
+// $Type defaults to Edm.String when it isn't present.
+func (f *jsonFacets) typeName() string {
+	typeName := "Edm.String"
+	if f.Type != nil {
+		typeName = *f.Type
+	}
+	if f.Collection {
+		return "Collection(" + typeName + ")"
+	}
+	return typeName
+}
+
+// DecodeJSON decodes an OData CSDL JSON document into the same model as the XML representation
+func DecodeJSON(r io.Reader) (*Edmx, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	members, err := decodeJSONObject(data)
+	if err != nil {
+		return nil, err
+	}
+	edmx := &Edmx{}
+	for _, member := range members {
+		switch {
+		case member.Key == "$Reference":
+			edmx.Reference, err = decodeJSONReferences(member.Value)
+		case strings.HasPrefix(member.Key, "$"):
+			// $Version and $EntityContainer, nothing we need
+			continue
+		default:
+			var schema Schema
+			schema, err = decodeJSONSchema(member.Key, member.Value)
+			edmx.DataServices.Schema = append(edmx.DataServices.Schema, schema)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", member.Key, err)
+		}
+	}
+	return edmx, nil
+}
+
+func decodeJSONReferences(data []byte) ([]Reference, error) {
+	members, err := decodeJSONObject(data)
+	if err != nil {
+		return nil, err
+	}
+	references := make([]Reference, 0, len(members))
+	for _, member := range members {
+		jsonRef := struct {
+			Include []struct {
+				Namespace string `json:"$Namespace"`
+				Alias     string `json:"$Alias"`
+			} `json:"$Include"`
+		}{}
+		err = json.Unmarshal(member.Value, &jsonRef)
+		if err != nil {
+			return nil, err
+		}
+		reference := Reference{Uri: member.Key}
+		for _, include := range jsonRef.Include {
+			reference.Include = append(reference.Include, Include{Namespace: include.Namespace, Alias: include.Alias})
+		}
+		references = append(references, reference)
+	}
+	return references, nil
+}
+
+func decodeJSONSchema(namespace string, data []byte) (Schema, error) {
+	schema := Schema{Namespace: namespace}
+	members, err := decodeJSONObject(data)
+	if err != nil {
+		return schema, err
+	}
+	schema.Annotation, err = decodeJSONAnnotations(members)
+	if err != nil {
+		return schema, err
+	}
+	for _, member := range members {
+		switch {
+		case member.Key == "$Alias":
+			err = json.Unmarshal(member.Value, &schema.Alias)
+		case member.Key == "$Annotations":
+			schema.Annotations, err = decodeJSONExternalAnnotations(member.Value)
+		case strings.HasPrefix(member.Key, "$"):
+			continue
+		case strings.HasPrefix(member.Key, "@"):
+			continue
+		case bytes.HasPrefix(bytes.TrimSpace(member.Value), []byte("[")):
+			// Actions and functions are arrays of overloads
+			err = decodeJSONOverloads(&schema, member.Key, member.Value)
+		default:
+			err = decodeJSONSchemaElement(&schema, member.Key, member.Value)
+		}
+		if err != nil {
+			return schema, fmt.Errorf("%s: %w", member.Key, err)
+		}
+	}
+	return schema, nil
+}
+
+func decodeJSONSchemaElement(schema *Schema, name string, data []byte) error {
+	facets := jsonFacets{}
+	err := json.Unmarshal(data, &facets)
+	if err != nil {
+		return err
+	}
+	members, err := decodeJSONObject(data)
+	if err != nil {
+		return err
+	}
+	switch facets.Kind {
+	case "EntityType":
+		entityType := EntityType{
+			Name:      name,
+			BaseType:  facets.BaseType,
+			Abstract:  facets.Abstract,
+			OpenType:  facets.OpenType,
+			HasStream: facets.HasStream,
+		}
+		entityType.Property, entityType.NavigationProperty, entityType.Annotation, err = decodeJSONStructuredMembers(members)
+		schema.EntityType = append(schema.EntityType, entityType)
+	case "ComplexType":
+		complexType := ComplexType{
+			Name:     name,
+			BaseType: facets.BaseType,
+			Abstract: facets.Abstract,
+			OpenType: facets.OpenType,
+		}
+		complexType.Property, complexType.NavigationProperty, complexType.Annotation, err = decodeJSONStructuredMembers(members)
+		schema.ComplexType = append(schema.ComplexType, complexType)
+	case "EnumType":
+		var enumType EnumType
+		enumType, err = decodeJSONEnumType(name, facets, members)
+		schema.EnumType = append(schema.EnumType, enumType)
+	case "TypeDefinition":
+		typeDefinition := TypeDefinition{
+			Name:           name,
+			UnderlyingType: facets.UnderlyingType,
+		}
+		typeDefinition.Annotation, err = decodeJSONAnnotations(members)
+		schema.TypeDefinition = append(schema.TypeDefinition, typeDefinition)
+	case "EntityContainer":
+		var container EntityContainer
+		container, err = decodeJSONEntityContainer(name, members)
+		schema.EntityContainer = append(schema.EntityContainer, container)
+	case "Term":
+		schema.Term = append(schema.Term, Term{Name: name, Type: facets.typeName()})
+	}
+	return err
+}
+
+// decodeJSONStructuredMembers returns the properties, navigation properties, and annotations of an
+// entity or complex type
+func decodeJSONStructuredMembers(members []jsonMember) ([]Property, []NavigationProperty, []Annotation, error) {
+	properties := []Property{}
+	navProps := []NavigationProperty{}
+	annotations, err := decodeJSONAnnotations(members)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	for _, member := range members {
+		if strings.HasPrefix(member.Key, "$") || strings.HasPrefix(member.Key, "@") {
+			continue
+		}
+		facets := jsonFacets{}
+		err := json.Unmarshal(member.Value, &facets)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("%s: %w", member.Key, err)
+		}
+		propMembers, err := decodeJSONObject(member.Value)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("%s: %w", member.Key, err)
+		}
+		propAnnotations, err := decodeJSONAnnotations(propMembers)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("%s: %w", member.Key, err)
+		}
+		// Unlike the XML, a missing $Nullable means false
+		nullable := facets.Nullable
+		if facets.Kind == "NavigationProperty" {
+			navProps = append(navProps, NavigationProperty{
+				Name:           member.Key,
+				Type:           facets.typeName(),
+				Nullable:       &nullable,
+				Partner:        facets.Partner,
+				ContainsTarget: facets.ContainsTarget,
+				Annotation:     propAnnotations,
+			})
+			continue
+		}
+		property := Property{
+			Name:       member.Key,
+			Type:       facets.typeName(),
+			Nullable:   &nullable,
+			MaxLength:  facets.MaxLength,
+			Precision:  facets.Precision,
+			Annotation: propAnnotations,
+		}
+		if facets.Unicode != nil {
+			property.Unicode = *facets.Unicode
+		}
+		if facets.DefaultValue != nil {
+			property.DefaultValue = fmt.Sprint(facets.DefaultValue)
+		}
+		properties = append(properties, property)
+	}
+	return properties, navProps, annotations, nil
+}
+
+func decodeJSONEnumType(name string, facets jsonFacets, members []jsonMember) (EnumType, error) {
+	enumType := EnumType{
+		Name:           name,
+		UnderlyingType: facets.UnderlyingType,
+		IsFlags:        facets.IsFlags,
+	}
+	var err error
+	enumType.Annotation, err = decodeJSONAnnotations(members)
+	if err != nil {
+		return enumType, err
+	}
+	for _, member := range members {
+		if strings.HasPrefix(member.Key, "$") || strings.HasPrefix(member.Key, "@") {
+			continue
+		}
+		// Annotations on members are siblings of the member, i.e. "On@Core.Description"
+		memberName, term, isAnnotation := strings.Cut(member.Key, "@")
+		if isAnnotation {
+			annotation, err := decodeJSONAnnotation("@"+term, member.Value)
+			if err != nil {
+				return enumType, err
+			}
+			for i := range enumType.Member {
+				if enumType.Member[i].Name == memberName {
+					enumType.Member[i].Annotation = append(enumType.Member[i].Annotation, annotation)
+				}
+			}
+			continue
+		}
+		enumMember := Member{Name: memberName}
+		// The XML leaves out values that are just the member's position, so do the same to get the same types
+		value := string(member.Value)
+		if facets.IsFlags || value != strconv.Itoa(len(enumType.Member)) {
+			enumMember.Value = &value
+		}
+		enumType.Member = append(enumType.Member, enumMember)
+	}
+	return enumType, nil
+}
+
+func decodeJSONEntityContainer(name string, members []jsonMember) (EntityContainer, error) {
+	container := EntityContainer{Name: name}
+	var err error
+	container.Annotation, err = decodeJSONAnnotations(members)
+	if err != nil {
+		return container, err
+	}
+	for _, member := range members {
+		switch {
+		case member.Key == "$Extends":
+			err := json.Unmarshal(member.Value, &container.Extends)
+			if err != nil {
+				return container, err
+			}
+			continue
+		case strings.HasPrefix(member.Key, "$"), strings.HasPrefix(member.Key, "@"):
+			continue
+		}
+		child := struct {
+			jsonFacets
+			NavigationPropertyBinding map[string]string `json:"$NavigationPropertyBinding"`
+		}{}
+		err := json.Unmarshal(member.Value, &child)
+		if err != nil {
+			return container, fmt.Errorf("%s: %w", member.Key, err)
+		}
+		childMembers, err := decodeJSONObject(member.Value)
+		if err != nil {
+			return container, fmt.Errorf("%s: %w", member.Key, err)
+		}
+		annotations, err := decodeJSONAnnotations(childMembers)
+		if err != nil {
+			return container, fmt.Errorf("%s: %w", member.Key, err)
+		}
+		bindings := []NavigationPropertyBinding{}
+		for _, path := range slices.Sorted(maps.Keys(child.NavigationPropertyBinding)) {
+			bindings = append(bindings, NavigationPropertyBinding{Path: path, Target: child.NavigationPropertyBinding[path]})
+		}
+		typeName := ""
+		if child.Type != nil {
+			typeName = *child.Type
+		}
+		// Entity sets are collections, singletons aren't
+		if child.Collection {
+			container.EntitySet = append(container.EntitySet, EntitySet{
+				Name:                      member.Key,
+				EntityType:                typeName,
+				NavigationPropertyBinding: bindings,
+				Annotation:                annotations,
+			})
+			continue
+		}
+		container.Singleton = append(container.Singleton, Singleton{
+			Name:                      member.Key,
+			Type:                      typeName,
+			NavigationPropertyBinding: bindings,
+			Annotation:                annotations,
+		})
+	}
+	return container, nil
+}
+
+func decodeJSONOverloads(schema *Schema, name string, data []byte) error {
+	overloads := []json.RawMessage{}
+	err := json.Unmarshal(data, &overloads)
+	if err != nil {
+		return err
+	}
+	for _, overload := range overloads {
+		facets := jsonFacets{}
+		err = json.Unmarshal(overload, &facets)
+		if err != nil {
+			return err
+		}
+		if facets.Kind != "Action" {
+			// Functions aren't modeled yet
+			continue
+		}
+		members, err := decodeJSONObject(overload)
+		if err != nil {
+			return err
+		}
+		action := Action{
+			Name:    name,
+			IsBound: facets.IsBound,
+		}
+		action.Annotation, err = decodeJSONAnnotations(members)
+		if err != nil {
+			return err
+		}
+		for _, member := range members {
+			if member.Key != "$Parameter" {
+				continue
+			}
+			action.Parameter, err = decodeJSONParameters(member.Value)
+			if err != nil {
+				return err
+			}
+		}
+		schema.Action = append(schema.Action, action)
+	}
+	return nil
+}
+
+func decodeJSONParameters(data []byte) ([]Parameter, error) {
+	rawParameters := []json.RawMessage{}
+	err := json.Unmarshal(data, &rawParameters)
+	if err != nil {
+		return nil, err
+	}
+	parameters := make([]Parameter, 0, len(rawParameters))
+	for _, rawParameter := range rawParameters {
+		facets := jsonFacets{}
+		err = json.Unmarshal(rawParameter, &facets)
+		if err != nil {
+			return nil, err
+		}
+		members, err := decodeJSONObject(rawParameter)
+		if err != nil {
+			return nil, err
+		}
+		nullable := facets.Nullable
+		parameter := Parameter{
+			Name:     facets.Name,
+			Type:     facets.typeName(),
+			Nullable: &nullable,
+		}
+		parameter.Annotation, err = decodeJSONAnnotations(members)
+		if err != nil {
+			return nil, err
+		}
+		parameters = append(parameters, parameter)
+	}
+	return parameters, nil
+}
+
+func decodeJSONExternalAnnotations(data []byte) ([]Annotations, error) {
+	members, err := decodeJSONObject(data)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]Annotations, 0, len(members))
+	for _, member := range members {
+		targetMembers, err := decodeJSONObject(member.Value)
+		if err != nil {
+			return nil, err
+		}
+		annotations := Annotations{Target: member.Key}
+		annotations.Annotation, err = decodeJSONAnnotations(targetMembers)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, annotations)
+	}
+	return ret, nil
+}
+
+// decodeJSONAnnotations returns the annotations, the members that start with "@", of an object.
+// Annotations of an annotation follow it, i.e. "@Core.Description@Core.IsLanguageDependent".
+func decodeJSONAnnotations(members []jsonMember) ([]Annotation, error) {
+	annotations := []Annotation{}
+	for _, member := range members {
+		if !strings.HasPrefix(member.Key, "@") {
+			continue
+		}
+		annotated, term, nested := strings.Cut(member.Key[1:], "@")
+		if !nested {
+			term = annotated
+		}
+		annotation, err := decodeJSONAnnotation("@"+term, member.Value)
+		if err != nil {
+			return nil, err
+		}
+		if !nested {
+			annotations = append(annotations, annotation)
+			continue
+		}
+		for i := range annotations {
+			if "@"+annotated == annotations[i].key() {
+				annotations[i].Annotation = append(annotations[i].Annotation, annotation)
+			}
+		}
+	}
+	return annotations, nil
+}
+
+// key returns the JSON member name of the annotation
+func (a Annotation) key() string {
+	if a.Qualifier != "" {
+		return "@" + a.Term + "#" + a.Qualifier
+	}
+	return "@" + a.Term
+}
+
+// decodeJSONAnnotation decodes a "@Term#Qualifier" member
+func decodeJSONAnnotation(key string, data []byte) (Annotation, error) {
+	term, qualifier, _ := strings.Cut(strings.TrimPrefix(key, "@"), "#")
+	expr, err := decodeJSONExpr(data)
+	if err != nil {
+		return Annotation{}, fmt.Errorf("%s: %w", key, err)
+	}
+	annotation := Annotation{
+		Term:      term,
+		Qualifier: qualifier,
+		Expr:      expr,
+	}
+	annotation.setConstant()
+	return annotation, nil
+}
+
+// decodeJSONExpr decodes an annotation expression. Dynamic expressions are objects with a member
+// named after the expression, like {"$Eq": [...]}, and any other object is a record.
+func decodeJSONExpr(data []byte) (Expr, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, errors.New("missing annotation value")
+	}
+	switch data[0] {
+	case '[':
+		items := []json.RawMessage{}
+		err := json.Unmarshal(data, &items)
+		if err != nil {
+			return nil, err
+		}
+		exprs, err := decodeJSONExprs(items)
+		return Collection{Items: exprs}, err
+	case '{':
+		members, err := decodeJSONObject(data)
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range members {
+			if slices.Contains(jsonExprNames, member.Key) {
+				return decodeJSONDynamicExpr(member, members)
+			}
+		}
+		return decodeJSONRecord(members)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var value any
+	err := dec.Decode(&value)
+	if err != nil {
+		return nil, err
+	}
+	switch value := value.(type) {
+	case nil:
+		return Null{}, nil
+	case string:
+		// Dates, durations, enum members and the like are strings too, the enum members are told
+		// apart by the term's type once it is resolved, see enumMembers
+		return Constant{Kind: StringKind, Value: value}, nil
+	case bool:
+		return Constant{Kind: BoolKind, Value: strconv.FormatBool(value)}, nil
+	case json.Number:
+		if strings.ContainsAny(value.String(), ".eE") {
+			return Constant{Kind: DecimalKind, Value: value.String()}, nil
+		}
+		return Constant{Kind: IntKind, Value: value.String()}, nil
+	}
+	return nil, fmt.Errorf("invalid annotation value %s", data)
+}
+
+func decodeJSONExprs(items []json.RawMessage) ([]Expr, error) {
+	exprs := make([]Expr, 0, len(items))
+	for _, item := range items {
+		expr, err := decodeJSONExpr(item)
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+	}
+	return exprs, nil
+}
+
+// jsonExprNames are the members that name an expression in an object, the others like $Type are
+// attributes of the expression
+var jsonExprNames = func() []string {
+	names := []string{"$EnumMember", "$LabeledElementReference", "$Apply", "$If", "$Cast", "$IsOf", "$LabeledElement", "$UrlRef"}
+	for _, name := range slices.Concat(pathKinds, binaryOperators, unaryOperators) {
+		names = append(names, "$"+name)
+	}
+	return names
+}()
+
+// decodeJSONDynamicExpr decodes the expression named by member, the other members are its
+// attributes like $Type or $Function and its annotations
+func decodeJSONDynamicExpr(member jsonMember, members []jsonMember) (Expr, error) {
+	name := strings.TrimPrefix(member.Key, "$")
+	annotations, err := decodeJSONAnnotations(members)
+	if err != nil {
+		return nil, err
+	}
+	attrs := map[string]string{}
+	for _, attr := range members {
+		var value string
+		if strings.HasPrefix(attr.Key, "$") && json.Unmarshal(attr.Value, &value) == nil {
+			attrs[attr.Key] = value
+		}
+	}
+	operands := func() ([]Expr, error) {
+		items := []json.RawMessage{}
+		err := json.Unmarshal(member.Value, &items)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", member.Key, err)
+		}
+		return decodeJSONExprs(items)
+	}
+	operand := func() (Expr, error) {
+		expr, err := decodeJSONExpr(member.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", member.Key, err)
+		}
+		return expr, nil
+	}
+	at := func(exprs []Expr, i int) Expr {
+		if i < len(exprs) {
+			return exprs[i]
+		}
+		return nil
+	}
+	switch {
+	case name == EnumMemberKind:
+		return Constant{Kind: EnumMemberKind, Value: attrs[member.Key]}, nil
+	case slices.Contains(pathKinds, name):
+		return PathExpr{Kind: name, Path: attrs[member.Key]}, nil
+	case name == "LabeledElementReference":
+		return LabeledElementReference{Name: attrs[member.Key]}, nil
+	case name == "Apply":
+		args, err := operands()
+		return Apply{Function: attrs["$Function"], Args: args, Annotation: annotations}, err
+	case name == "If":
+		exprs, err := operands()
+		return If{Condition: at(exprs, 0), Then: at(exprs, 1), Else: at(exprs, 2), Annotation: annotations}, err
+	case slices.Contains(binaryOperators, name):
+		exprs, err := operands()
+		return BinaryExpr{Operator: name, Left: at(exprs, 0), Right: at(exprs, 1), Annotation: annotations}, err
+	}
+	expr, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	switch name {
+	case "Cast", "IsOf":
+		return Cast{IsOf: name == "IsOf", Type: attrs["$Type"], Value: expr, Annotation: annotations}, nil
+	case "LabeledElement":
+		return LabeledElement{Name: attrs["$Name"], Value: expr, Annotation: annotations}, nil
+	case "UrlRef":
+		return UrlRef{Value: expr, Annotation: annotations}, nil
+	case "Not", "Neg":
+		return UnaryExpr{Operator: name, Operand: expr, Annotation: annotations}, nil
+	}
+	return nil, fmt.Errorf("unknown expression %s", member.Key)
+}
+
+// decodeJSONRecord decodes a record expression, "@type" is the record type and the other members
+// are either annotations of the record or its property values
+func decodeJSONRecord(members []jsonMember) (Expr, error) {
+	record := Record{}
+	annotationMembers := []jsonMember{}
+	for _, member := range members {
+		if member.Key == "@type" {
+			var recordType string
+			err := json.Unmarshal(member.Value, &recordType)
+			if err != nil {
+				return nil, fmt.Errorf("@type: %w", err)
+			}
+			// The type is a URL whose fragment is the qualified name
+			_, fragment, found := strings.Cut(recordType, "#")
+			if found {
+				recordType = fragment
+			}
+			record.Type = recordType
+			continue
+		}
+		if strings.HasPrefix(member.Key, "@") {
+			annotationMembers = append(annotationMembers, member)
+			continue
+		}
+		property, term, annotated := strings.Cut(member.Key, "@")
+		if annotated {
+			// Annotations of a property value are siblings of it, i.e. "Kind@Core.Description"
+			annotation, err := decodeJSONAnnotation("@"+term, member.Value)
+			if err != nil {
+				return nil, err
+			}
+			for i := range record.PropertyValue {
+				if record.PropertyValue[i].Property == property {
+					record.PropertyValue[i].Annotation = append(record.PropertyValue[i].Annotation, annotation)
+				}
+			}
+			continue
+		}
+		expr, err := decodeJSONExpr(member.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", member.Key, err)
+		}
+		record.PropertyValue = append(record.PropertyValue, PropertyValue{Property: member.Key, Value: expr})
+	}
+	annotations, err := decodeJSONAnnotations(annotationMembers)
+	record.Annotation = annotations
+	return record, err
+}
@@ -1,25 +1,41 @@
 package csdl
 
 import (
+	"bufio"
+	"bytes"
 	"cmp"
 	"encoding/xml"
+	"fmt"
 	"io"
 	"maps"
 	"slices"
 	"strconv"
 	"strings"
+	"unicode"
 )
 
 type Parser struct {
 	IgnoreCollections bool
-	Files             map[string]io.ReadCloser
-	Replacements      map[string]string
+	// TypedLinks marks navigation properties to be generated as Link[T] instead of OdataID
+	TypedLinks bool
+	// Handlers marks resources to be generated with server interfaces and routes
+	Handlers bool
+	// StrictEnums marks enums to be generated with an UnmarshalJSON that rejects unknown values
+	StrictEnums bool
+	// FieldVersions marks types to be generated with the version each property was added in
+	FieldVersions bool
+	Files         map[string]io.ReadCloser
+	Replacements  map[string]string
+	// Diagnostics are the problems found by Parse
+	Diagnostics Diagnostics
+	fileNames   map[string]string
 }
 
 func NewParser() *Parser {
 	return &Parser{
 		Files:        make(map[string]io.ReadCloser),
 		Replacements: make(map[string]string),
+		fileNames:    make(map[string]string),
 	}
 }
 
@@ -30,33 +46,146 @@ func (p *Parser) Close() error {
 	return nil
 }
 
+// AddFile adds a CSDL document to be parsed, it is keyed by its name without the .xml or .json so
+// a file added with the same key replaces the earlier one
 func (p *Parser) AddFile(name string, file io.ReadCloser) {
-	name = strings.TrimSuffix(name, ".xml")
+	fileName := name
+	name = strings.TrimSuffix(strings.TrimSuffix(name, ".xml"), ".json")
+	replaced, ok := p.Files[name]
+	if ok {
+		_ = replaced.Close()
+		p.Diagnostics.add(Warning, cmp.Or(p.fileNames[name], name), 0, name, "replaced by %s, only one document with the name is parsed", fileName)
+	}
 	p.Files[name] = file
+	p.fileNames[name] = fileName
+}
+
+// decodeFile decodes either the XML or the JSON representation of CSDL, whichever the file
+// starts with
+func decodeFile(reader io.Reader) (*Edmx, error) {
+	buffered := bufio.NewReader(reader)
+	bom, err := buffered.Peek(3)
+	if err == nil && bytes.Equal(bom, []byte{0xEF, 0xBB, 0xBF}) {
+		// The UTF-8 byte order mark
+		_, _ = buffered.Discard(3)
+	}
+	for {
+		b, err := buffered.Peek(1)
+		if err != nil {
+			return nil, err
+		}
+		if !unicode.IsSpace(rune(b[0])) {
+			if b[0] == '{' {
+				return DecodeJSON(buffered)
+			}
+			break
+		}
+		_, _ = buffered.ReadByte()
+	}
+	dec := xml.NewDecoder(buffered)
+	edmx := &Edmx{}
+	err = dec.Decode(edmx)
+	if err != nil {
+		return nil, err
+	}
+	return edmx, nil
 }
 
 func (p *Parser) Parse() (map[string]*Type, error) {
 	types := map[string]*Type{}
-	for _, reader := range p.Files {
-		dec := xml.NewDecoder(reader)
-		edmx := Edmx{}
-		err := dec.Decode(&edmx)
+	boundActions := map[string][]ActionType{}
+	singletons := []Singleton{}
+	documents := map[string]*Edmx{}
+	for _, name := range slices.Sorted(maps.Keys(p.Files)) {
+		fileName := cmp.Or(p.fileNames[name], name)
+		edmx, err := decodeFile(p.Files[name])
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("%s: %w", fileName, err)
 		}
-		for _, schema := range edmx.DataServices.Schema {
+		documents[fileName] = edmx
+	}
+	// Terms can be defined in any of the documents
+	vocabulary := newVocabulary(documents)
+	for _, fileName := range slices.Sorted(maps.Keys(documents)) {
+		// Rewrite aliases to namespaces so names can be looked up across documents
+		symbols := newSymbolTable(fileName, documents[fileName], vocabulary)
+		symbols.resolve(documents[fileName])
+		p.Diagnostics = append(p.Diagnostics, symbols.diagnostics...)
+	}
+	p.Diagnostics = append(p.Diagnostics, applyExternalAnnotations(documents)...)
+	// Go in file name order so that duplicate definitions always resolve the same way
+	for _, fileName := range slices.Sorted(maps.Keys(documents)) {
+		for _, schema := range documents[fileName].DataServices.Schema {
 			for _, entityType := range schema.EntityType {
-				types[schema.Namespace+"."+entityType.Name] = NewTypeFromEntityType(entityType, schema.Namespace)
+				if p.IgnoreCollections && isResourceCollection(entityType) {
+					continue
+				}
+				types[schema.Namespace+"."+entityType.Name] = NewTypeFromEntityType(entityType, schema.Namespace).setFile(fileName)
 			}
 			for _, enumType := range schema.EnumType {
-				types[schema.Namespace+"."+enumType.Name] = NewTypeFromEnumType(enumType, schema.Namespace)
+				types[schema.Namespace+"."+enumType.Name] = NewTypeFromEnumType(enumType, schema.Namespace).setFile(fileName)
 			}
 			for _, complexType := range schema.ComplexType {
-				types[schema.Namespace+"."+complexType.Name] = NewTypeFromComplexType(complexType, schema.Namespace)
+				types[schema.Namespace+"."+complexType.Name] = NewTypeFromComplexType(complexType, schema.Namespace).setFile(fileName)
 			}
 			for _, typeDefinition := range schema.TypeDefinition {
 				p.Replacements[schema.Namespace+"."+typeDefinition.Name] = typeDefinition.UnderlyingType
 			}
+			for _, container := range schema.EntityContainer {
+				singletons = append(singletons, container.Singleton...)
+			}
+			for _, action := range schema.Action {
+				requestType := NewTypeFromAction(action, schema.Namespace).setFile(fileName)
+				types[schema.Namespace+"."+requestType.Name] = requestType
+				if action.IsBound && len(action.Parameter) > 0 {
+					// The binding parameter is the Actions complex type the action shows up in
+					bindingType := action.Parameter[0].Type
+					boundActions[bindingType] = append(boundActions[bindingType], ActionType{Name: action.Name, Namespace: schema.Namespace, Description: requestType.Description})
+				}
+			}
+		}
+	}
+	for _, bindingType := range slices.Sorted(maps.Keys(boundActions)) {
+		actions := boundActions[bindingType]
+		t, ok := types[bindingType]
+		if !ok {
+			for _, action := range actions {
+				request := types[action.Namespace+"."+action.Name+"Request"]
+				p.Diagnostics.add(Warning, request.File, request.Line, action.Namespace+"."+action.Name, "bound to %s which isn't defined, the action is left out", bindingType)
+			}
+			continue
+		}
+		t.Actions = append(t.Actions, actions...)
+	}
+	// Older schemas don't have Redfish.Uris, but the resources the service root links to are
+	// singletons named after their path
+	for _, singleton := range singletons {
+		t, ok := types[singleton.Type]
+		if !ok {
+			continue
+		}
+		uris := []string{"/redfish/v1/" + singleton.Name}
+		if t.Name == "ServiceRoot" {
+			uris = []string{"/redfish/v1"}
+		}
+		// Newer versions derive from the unversioned type rather than the one the singleton names
+		for _, name := range []string{singleton.Type, unversionedNamespace(t.Namespace) + "." + t.Name} {
+			t, ok = types[name]
+			if ok && len(t.Uris) == 0 {
+				t.Uris = uris
+			}
+		}
+	}
+	for _, t := range types {
+		t.Handlers = p.Handlers
+		t.StrictEnums = p.StrictEnums
+		t.FieldVersions = p.FieldVersions
+		if !p.TypedLinks {
+			continue
+		}
+		for name, prop := range t.Properties {
+			prop.TypedLink = prop.Navigation
+			t.Properties[name] = prop
 		}
 	}
 	return types, nil
@@ -64,8 +193,7 @@ func (p *Parser) Parse() (map[string]*Type, error) {
 
 // Fold will consolidate types, adding properties from base types to derived types
 func (p *Parser) Fold(types map[string]*Type) {
-	keys := maps.Keys(types)
-	sortedKeys := slices.SortedFunc(keys, sortNamespace)
+	sortedKeys := SortedNames(types)
 	slices.Reverse(sortedKeys)
 	for _, name := range sortedKeys {
 		t, ok := types[name]
@@ -75,11 +203,18 @@ func (p *Parser) Fold(types map[string]*Type) {
 		}
 		types[name] = t.Fold(types, p.Replacements)
 		if types[name] == nil {
+			// Fold leaves the base type it couldn't find in t
+			p.Diagnostics.add(Warning, t.File, t.Line, name, "base type %s isn't defined, nothing is generated for it", t.BaseType)
 			delete(types, name)
 		}
 	}
 }
 
+// SortedNames returns the type names ordered by namespace and then version, oldest first
+func SortedNames(types map[string]*Type) []string {
+	return slices.SortedFunc(maps.Keys(types), sortNamespace)
+}
+
 // need to do this because simple string sort doesn't work...
 func sortNamespace(a, b string) int {
 	a1, a2, a3 := splitNamespace(a)
@@ -0,0 +1,61 @@
+package csdl
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestDecodeFileByteOrderMark(t *testing.T) {
+	tests := map[string]string{
+		"XML":  `<?xml version="1.0" encoding="UTF-8"?><edmx:Edmx xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx" Version="4.0"><edmx:DataServices><Schema xmlns="http://docs.oasis-open.org/odata/ns/edm" Namespace="Test"/></edmx:DataServices></edmx:Edmx>`,
+		"JSON": `{"$Version": "4.01", "Test": {}}`,
+	}
+	for name, document := range tests {
+		for _, prefix := range []string{"", "\xEF\xBB\xBF", "\xEF\xBB\xBF\n  "} {
+			edmx, err := decodeFile(strings.NewReader(prefix + document))
+			if err != nil {
+				t.Errorf("%s with prefix %q: %v", name, prefix, err)
+				continue
+			}
+			if len(edmx.DataServices.Schema) != 1 || edmx.DataServices.Schema[0].Namespace != "Test" {
+				t.Errorf("%s with prefix %q: got schemas %+v", name, prefix, edmx.DataServices.Schema)
+			}
+		}
+	}
+}
+
+func TestAddFileCollision(t *testing.T) {
+	parser := NewParser()
+	defer parser.Close()
+	parser.AddFile("Test_v1.xml", io.NopCloser(strings.NewReader("")))
+	parser.AddFile("Test_v1.json", io.NopCloser(strings.NewReader(`{"$Version": "4.01", "Test": {}}`)))
+	if len(parser.Diagnostics) != 1 || parser.Diagnostics[0].File != "Test_v1.xml" || !strings.Contains(parser.Diagnostics[0].Message, "Test_v1.json") {
+		t.Fatalf("got diagnostics %v, want one saying Test_v1.xml was replaced by Test_v1.json", parser.Diagnostics)
+	}
+	_, err := parser.Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFoldUnknownBaseType(t *testing.T) {
+	parser := NewParser()
+	defer parser.Close()
+	parser.AddFile("Orphan_v1.json", io.NopCloser(strings.NewReader(`{
+		"$Version": "4.01",
+		"$Reference": {"Missing": {"$Include": [{"$Namespace": "Missing.v1_0_0"}]}},
+		"Orphan.v1_0_0": {"Orphan": {"$Kind": "ComplexType", "$BaseType": "Missing.v1_0_0.Missing"}}
+	}`)))
+	types, err := parser.Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parser.Fold(types)
+	if _, ok := types["Orphan.v1_0_0.Orphan"]; ok {
+		t.Error("the type with an unknown base type is still there")
+	}
+	if len(parser.Diagnostics) != 1 || parser.Diagnostics[0].Name != "Orphan.v1_0_0.Orphan" || !strings.Contains(parser.Diagnostics[0].Message, "Missing.v1_0_0.Missing") {
+		t.Errorf("got diagnostics %v, want one naming Orphan.v1_0_0.Orphan and Missing.v1_0_0.Missing", parser.Diagnostics)
+	}
+}
@@ -0,0 +1,163 @@
+package csdl
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// Values of the OData.Permissions annotation
+const (
+	permissionRead      = "Read"
+	permissionWrite     = "Write"
+	permissionReadWrite = "ReadWrite"
+)
+
+// permissions returns the OData.Permissions annotation as one of the permission values, or ""
+// if the property isn't annotated
+func permissions(annotations []Annotation) string {
+	annotation, ok := findAnnotation(annotations, permissionsTerm)
+	if !ok {
+		return ""
+	}
+	read, write := false, false
+	// The value is one or more enum members, i.e. "OData.Permission/Read OData.Permission/Write"
+	members, _ := annotationValue(annotation).(EnumValue)
+	for _, member := range members {
+		switch member[strings.LastIndex(member, "/")+1:] {
+		case permissionRead:
+			read = true
+		case permissionWrite:
+			write = true
+		case permissionReadWrite:
+			read, write = true, true
+		}
+	}
+	switch {
+	case read && write:
+		return permissionReadWrite
+	case write:
+		return permissionWrite
+	case read:
+		return permissionRead
+	}
+	return "None"
+}
+
+func (p *PropType) writable() bool {
+	return p.Permissions == permissionReadWrite || p.Permissions == permissionWrite
+}
+
+// nestedStruct returns the generated struct for a complex type property
+func (p *PropType) nestedStruct(replacements map[string]string, types map[string]*Type) (*Type, bool) {
+	if p.Navigation {
+		return nil, false
+	}
+	typeName := strings.TrimSuffix(strings.TrimPrefix(p.Type, "Collection("), ")")
+	rep, ok := replacements[typeName]
+	if ok {
+		typeName = rep
+	}
+	typeData, ok := doTypeSearch(typeName, types)
+	if !ok {
+		return nil, false
+	}
+	return findGeneratedStruct(typeData.GoTypeName(), types)
+}
+
+// hasWritable returns true if the type has a writable property, either directly or in a nested
+// complex type
+func (t *Type) hasWritable(types map[string]*Type) bool {
+	return t.anyProperty(types, (*PropType).writable, map[*Type]bool{})
+}
+
+// anyProperty returns true if match is true for a property of the type or of a nested complex
+// type. seen stops recursive types from looping forever.
+func (t *Type) anyProperty(types map[string]*Type, match func(*PropType) bool, seen map[*Type]bool) bool {
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+	for _, prop := range t.Properties {
+		if match(&prop) {
+			return true
+		}
+		nested, ok := prop.nestedStruct(t.Replacements, types)
+		if ok && nested.anyProperty(types, match, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+// patchNodes generates the <Type>Patch struct, with only the writable properties, and the table of
+// property permissions
+func (t *Type) patchNodes(types map[string]*Type, props []propField) []ast.Node {
+	ret := []ast.Node{}
+	if t.Request {
+		return ret
+	}
+	table := &strings.Builder{}
+	for _, prop := range props {
+		if prop.Prop.Permissions != "" {
+			table.WriteString(strconv.Quote(prop.jsonName()) + ": Permission_" + prop.Prop.Permissions + ",\n")
+		}
+	}
+	if table.Len() != 0 {
+		name := t.GoTypeName()
+		ret = append(ret, &sourceNode{src: `
+var permissions` + name + ` = map[string]Permission{
+` + table.String() + `}
+
+// PropertyPermission returns the OData.Permissions of a property by its JSON name, false if the
+// schema doesn't say
+func (*` + name + `) PropertyPermission(property string) (Permission, bool) {
+	permission, ok := permissions` + name + `[property]
+	return permission, ok
+}
+`})
+	}
+	if !t.hasWritable(types) {
+		return ret
+	}
+	structType := &ast.StructType{
+		Fields: &ast.FieldList{},
+	}
+	for _, prop := range props {
+		prefix, base := prop.goType()
+		var goType string
+		switch {
+		case prop.Prop.writable() && (base == "any" || strings.HasPrefix(base, "map[")):
+			goType = base
+		case prop.Prop.writable():
+			goType = "*" + strings.TrimPrefix(prefix, "*") + base
+		default:
+			nested, ok := prop.Prop.nestedStruct(t.Replacements, types)
+			if !ok || !nested.hasWritable(types) {
+				continue
+			}
+			if prefix == "[]" {
+				goType = "[]" + base + "Patch"
+			} else {
+				goType = "*" + base + "Patch"
+			}
+		}
+		structType.Fields.List = append(structType.Fields.List, &ast.Field{
+			Doc:   prop.Field.Doc,
+			Names: []*ast.Ident{ast.NewIdent(prop.Name)},
+			Type:  &ast.Ident{Name: goType},
+			Tag:   jsonTag(prop.jsonName(), true),
+		})
+	}
+	return append(ret, &ast.GenDecl{
+		Doc: docComment(t.GoTypeName() + "Patch holds the writable properties of " + t.GoTypeName() + ", only the fields that are set are sent in a PATCH request."),
+		Tok: token.TYPE,
+		Specs: []ast.Spec{
+			&ast.TypeSpec{
+				Name: ast.NewIdent(t.GoTypeName() + "Patch"),
+				Type: structType,
+			},
+		},
+	})
+}
@@ -0,0 +1,47 @@
+package csdl
+
+import (
+	"go/ast"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// registryNode generates an init that registers the structs in the file with Decode under every
+// @odata.type they are generated for, which is each version of the type along with the unversioned
+// name, false if the file doesn't have any
+func (f *File) registryNode(types map[string]*Type) (ast.Node, bool) {
+	qualifiedNames := map[string][]string{}
+	for qualifiedName, t := range types {
+		if !t.Request && len(t.Members) == 0 {
+			qualifiedNames[t.GoTypeName()] = append(qualifiedNames[t.GoTypeName()], qualifiedName)
+		}
+	}
+	src := &strings.Builder{}
+	for _, name := range slices.Sorted(maps.Keys(f.types)) {
+		t, ok := f.types[name].structType(types)
+		if !ok || t.Request {
+			continue
+		}
+		odataTypes := []string{"#" + unversionedNamespace(t.Namespace) + "." + t.Name}
+		for _, qualifiedName := range qualifiedNames[name] {
+			odataTypes = append(odataTypes, "#"+qualifiedName)
+		}
+		slices.SortFunc(odataTypes, sortNamespace)
+		for _, odataType := range slices.Compact(odataTypes) {
+			src.WriteString(strconv.Quote(odataType) + `: newType[` + name + `],
+`)
+		}
+	}
+	if src.Len() == 0 {
+		return nil, false
+	}
+	return &sourceNode{src: `
+// init registers the types in this file with Decode by their @odata.type
+func init() {
+	registerTypes(map[string]func() any{
+` + src.String() + `	})
+}
+`}, true
+}
@@ -0,0 +1,105 @@
+package csdl
+
+import (
+	"go/ast"
+	"strings"
+)
+
+// handlerNodes generates the interfaces a server implements to handle requests for a resource and
+// registers the routes for its URIs with NewRouter. Resources get a Getter, a Patcher if they have
+// writable properties, and a Deleter. Collections get a Getter and a Poster that creates members.
+func (t *Type) handlerNodes(types map[string]*Type) []ast.Node {
+	if !t.Handlers || len(t.Uris) == 0 || t.ComplexType || t.Request {
+		return []ast.Node{}
+	}
+	name := t.GoTypeName()
+	src := &strings.Builder{}
+	src.WriteString(`
+// ` + name + `Getter handles GET requests for ` + name + ` resources, see NewRouter
+type ` + name + `Getter interface {
+	Get` + name + `(r *Request) (*` + name + `, error)
+}
+`)
+	if t.Collection {
+		member := "OdataID"
+		body := "OdataID"
+		members, ok := t.Properties["Members"]
+		if ok {
+			memberType, ok := generatedStruct(strings.TrimSuffix(strings.TrimPrefix(members.Type, "Collection("), ")"), types)
+			if ok {
+				member = memberType.GoTypeName()
+				body = member
+				if memberType.hasRequiredOnCreate(types) {
+					body = member + "Create"
+				}
+			}
+		}
+		src.WriteString(`
+// ` + name + `Poster handles POST requests that create members of ` + name + `
+type ` + name + `Poster interface {
+	Post` + name + `(r *Request, body *` + body + `) (*` + member + `, error)
+}
+
+func init() {
+	routes = append(routes, func(mux *serveMux, server any) {
+		getter, ok := server.(` + name + `Getter)
+		if !ok {
+			return
+		}
+		var post func(*Request, *` + body + `) (*` + member + `, error)
+		poster, ok := server.(` + name + `Poster)
+		if ok {
+			post = poster.Post` + name + `
+		}
+		for _, uri := range ` + name + `_URIs {
+			handleCollection[` + name + `, ` + body + `, ` + member + `](mux, uri, getter.Get` + name + `, post)
+		}
+	})
+}
+`)
+		return []ast.Node{&sourceNode{src: src.String()}}
+	}
+	patch := "struct{}"
+	if t.hasWritable(types) {
+		patch = name + "Patch"
+		src.WriteString(`
+// ` + name + `Patcher handles PATCH requests for ` + name + ` resources
+type ` + name + `Patcher interface {
+	Patch` + name + `(r *Request, patch *` + patch + `) (*` + name + `, error)
+}
+`)
+	}
+	src.WriteString(`
+// ` + name + `Deleter handles DELETE requests for ` + name + ` resources
+type ` + name + `Deleter interface {
+	Delete` + name + `(r *Request) error
+}
+
+func init() {
+	routes = append(routes, func(mux *serveMux, server any) {
+		getter, ok := server.(` + name + `Getter)
+		if !ok {
+			return
+		}
+		var patch func(*Request, *` + patch + `) (*` + name + `, error)
+`)
+	if patch != "struct{}" {
+		src.WriteString(`		patcher, ok := server.(` + name + `Patcher)
+		if ok {
+			patch = patcher.Patch` + name + `
+		}
+`)
+	}
+	src.WriteString(`		var del func(*Request) error
+		deleter, ok := server.(` + name + `Deleter)
+		if ok {
+			del = deleter.Delete` + name + `
+		}
+		for _, uri := range ` + name + `_URIs {
+			handleResource[` + name + `, ` + patch + `](mux, uri, getter.Get` + name + `, patch, del)
+		}
+	})
+}
+`)
+	return []ast.Node{&sourceNode{src: src.String()}}
+}
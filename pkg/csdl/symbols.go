@@ -0,0 +1,289 @@
+package csdl
+
+import "strings"
+
+// knownVocabularies are the aliases the generator looks for terms under, documents are free to use
+// their own aliases so terms are rewritten to these
+var knownVocabularies = map[string]string{
+	"Org.OData.Core.V1":         "OData",
+	"Org.OData.Capabilities.V1": "Capabilities",
+	"Org.OData.Measures.V1":     "Measures",
+	"RedfishExtensions.v1_0_0":  "Redfish",
+	"Validation.v1_0_0":         "Validation",
+}
+
+// symbolTable resolves the qualified names used in a single CSDL document. Names are qualified
+// either by a namespace defined or included by the document or by an alias for one.
+type symbolTable struct {
+	file        string
+	aliases     map[string]string
+	namespaces  map[string]bool
+	vocabulary  *vocabulary
+	diagnostics Diagnostics
+}
+
+// newSymbolTable returns the symbol table of a document, the vocabulary has the terms of all the
+// loaded documents for telling which annotation values are enum members
+func newSymbolTable(file string, edmx *Edmx, vocabulary *vocabulary) *symbolTable {
+	s := &symbolTable{
+		file:       file,
+		aliases:    map[string]string{},
+		namespaces: map[string]bool{"Edm": true},
+		vocabulary: vocabulary,
+	}
+	for _, reference := range edmx.Reference {
+		for _, include := range reference.Include {
+			s.namespaces[include.Namespace] = true
+			if include.Alias != "" {
+				s.aliases[include.Alias] = include.Namespace
+			}
+		}
+	}
+	for _, schema := range edmx.DataServices.Schema {
+		s.namespaces[schema.Namespace] = true
+		if schema.Alias != "" {
+			s.aliases[schema.Alias] = schema.Namespace
+		}
+	}
+	return s
+}
+
+// resolveNamespace returns the namespace a qualifier refers to
+func (s *symbolTable) resolveNamespace(qualifier string) (string, bool) {
+	namespace, ok := s.aliases[qualifier]
+	if ok {
+		return namespace, true
+	}
+	return qualifier, s.namespaces[qualifier]
+}
+
+// resolveType returns the namespace qualified form of a type name, keeping any Collection() around it
+func (s *symbolTable) resolveType(name string, referencedBy string, line int) string {
+	if name == "" {
+		return name
+	}
+	if strings.HasPrefix(name, "Collection(") && strings.HasSuffix(name, ")") {
+		return "Collection(" + s.resolveType(name[len("Collection("):len(name)-1], referencedBy, line) + ")"
+	}
+	index := strings.LastIndex(name, ".")
+	if index == -1 {
+		s.report(name, referencedBy, line)
+		return name
+	}
+	namespace, ok := s.resolveNamespace(name[:index])
+	if !ok {
+		s.report(name, referencedBy, line)
+		return name
+	}
+	return namespace + name[index:]
+}
+
+// resolveTerm returns a term qualified with the alias the generator knows the vocabulary by, or with
+// the vocabulary's namespace if it isn't one of the known vocabularies
+func (s *symbolTable) resolveTerm(term string, referencedBy string, line int) string {
+	index := strings.LastIndex(term, ".")
+	if index == -1 {
+		s.report(term, referencedBy, line)
+		return term
+	}
+	namespace, ok := s.resolveNamespace(term[:index])
+	if !ok {
+		s.report(term, referencedBy, line)
+		return term
+	}
+	return knownName(namespace + term[index:])
+}
+
+// knownName returns a namespace qualified name qualified with the alias the generator knows the
+// namespace by, if it is one of the known vocabularies
+func knownName(name string) string {
+	index := strings.LastIndex(name, ".")
+	if index == -1 {
+		return name
+	}
+	alias, ok := knownVocabularies[name[:index]]
+	if ok {
+		return alias + name[index:]
+	}
+	return name
+}
+
+func (s *symbolTable) report(name string, referencedBy string, line int) {
+	s.diagnostics.add(Warning, s.file, line, referencedBy, "unresolved name %s, it isn't qualified by an included namespace or alias", name)
+}
+
+func (s *symbolTable) resolveAnnotations(annotations []Annotation, referencedBy string, line int) {
+	for i := range annotations {
+		annotations[i].Term = s.resolveTerm(annotations[i].Term, referencedBy, line)
+		annotations[i].Expr = enumMembers(s.resolveExpr(annotations[i].Expr, referencedBy, line), annotations[i].Term, s.vocabulary)
+		annotations[i].setConstant()
+		s.resolveAnnotations(annotations[i].Annotation, referencedBy, line)
+	}
+}
+
+// resolveExpr returns the expression with the qualified names in it resolved. Records are instances
+// of vocabulary types so they are qualified the same way terms are, casts are to model types.
+func (s *symbolTable) resolveExpr(expr Expr, referencedBy string, line int) Expr {
+	resolve := func(expr Expr) Expr {
+		return s.resolveExpr(expr, referencedBy, line)
+	}
+	switch expr := expr.(type) {
+	case Null:
+		s.resolveAnnotations(expr.Annotation, referencedBy, line)
+	case Collection:
+		for i := range expr.Items {
+			expr.Items[i] = resolve(expr.Items[i])
+		}
+	case Record:
+		if expr.Type != "" {
+			expr.Type = s.resolveTerm(expr.Type, referencedBy, line)
+		}
+		s.resolveAnnotations(expr.Annotation, referencedBy, line)
+		for i := range expr.PropertyValue {
+			expr.PropertyValue[i].Value = resolve(expr.PropertyValue[i].Value)
+			s.resolveAnnotations(expr.PropertyValue[i].Annotation, referencedBy, line)
+		}
+		return expr
+	case Apply:
+		s.resolveAnnotations(expr.Annotation, referencedBy, line)
+		for i := range expr.Args {
+			expr.Args[i] = resolve(expr.Args[i])
+		}
+	case Cast:
+		expr.Type = s.resolveType(expr.Type, referencedBy, line)
+		expr.Value = resolve(expr.Value)
+		s.resolveAnnotations(expr.Annotation, referencedBy, line)
+		return expr
+	case If:
+		expr.Condition, expr.Then, expr.Else = resolve(expr.Condition), resolve(expr.Then), resolve(expr.Else)
+		s.resolveAnnotations(expr.Annotation, referencedBy, line)
+		return expr
+	case BinaryExpr:
+		expr.Left, expr.Right = resolve(expr.Left), resolve(expr.Right)
+		s.resolveAnnotations(expr.Annotation, referencedBy, line)
+		return expr
+	case UnaryExpr:
+		expr.Operand = resolve(expr.Operand)
+		s.resolveAnnotations(expr.Annotation, referencedBy, line)
+		return expr
+	case LabeledElement:
+		expr.Value = resolve(expr.Value)
+		s.resolveAnnotations(expr.Annotation, referencedBy, line)
+		return expr
+	case UrlRef:
+		expr.Value = resolve(expr.Value)
+		s.resolveAnnotations(expr.Annotation, referencedBy, line)
+		return expr
+	}
+	return expr
+}
+
+// resolve rewrites every qualified name in the document to use the full namespace
+func (s *symbolTable) resolve(edmx *Edmx) {
+	for i := range edmx.DataServices.Schema {
+		schema := &edmx.DataServices.Schema[i]
+		s.resolveAnnotations(schema.Annotation, schema.Namespace, 0)
+		for j := range schema.Term {
+			term := &schema.Term[j]
+			term.Type = s.resolveType(term.Type, schema.Namespace+"."+term.Name, 0)
+		}
+		for j := range schema.EntityType {
+			entityType := &schema.EntityType[j]
+			name := schema.Namespace + "." + entityType.Name
+			entityType.BaseType = s.resolveType(entityType.BaseType, name, entityType.Line)
+			s.resolveAnnotations(entityType.Annotation, name, entityType.Line)
+			s.resolveProperties(entityType.Property, name)
+			s.resolveNavigationProperties(entityType.NavigationProperty, name)
+		}
+		for j := range schema.ComplexType {
+			complexType := &schema.ComplexType[j]
+			name := schema.Namespace + "." + complexType.Name
+			complexType.BaseType = s.resolveType(complexType.BaseType, name, complexType.Line)
+			s.resolveAnnotations(complexType.Annotation, name, complexType.Line)
+			s.resolveProperties(complexType.Property, name)
+			s.resolveNavigationProperties(complexType.NavigationProperty, name)
+		}
+		for j := range schema.EnumType {
+			enumType := &schema.EnumType[j]
+			name := schema.Namespace + "." + enumType.Name
+			enumType.UnderlyingType = s.resolveType(enumType.UnderlyingType, name, enumType.Line)
+			s.resolveAnnotations(enumType.Annotation, name, enumType.Line)
+			for k := range enumType.Member {
+				s.resolveAnnotations(enumType.Member[k].Annotation, name+"/"+enumType.Member[k].Name, enumType.Line)
+			}
+		}
+		for j := range schema.TypeDefinition {
+			typeDefinition := &schema.TypeDefinition[j]
+			name := schema.Namespace + "." + typeDefinition.Name
+			typeDefinition.UnderlyingType = s.resolveType(typeDefinition.UnderlyingType, name, typeDefinition.Line)
+			s.resolveAnnotations(typeDefinition.Annotation, name, typeDefinition.Line)
+		}
+		for j := range schema.Action {
+			action := &schema.Action[j]
+			name := schema.Namespace + "." + action.Name
+			s.resolveAnnotations(action.Annotation, name, action.Line)
+			for k := range action.Parameter {
+				parameter := &action.Parameter[k]
+				parameter.Type = s.resolveType(parameter.Type, name+"/"+parameter.Name, parameter.Line)
+				s.resolveAnnotations(parameter.Annotation, name+"/"+parameter.Name, parameter.Line)
+			}
+		}
+		for j := range schema.EntityContainer {
+			container := &schema.EntityContainer[j]
+			name := schema.Namespace + "." + container.Name
+			container.Extends = s.resolveType(container.Extends, name, 0)
+			s.resolveAnnotations(container.Annotation, name, 0)
+			for k := range container.EntitySet {
+				entitySet := &container.EntitySet[k]
+				entitySet.EntityType = s.resolveType(entitySet.EntityType, name+"/"+entitySet.Name, 0)
+				s.resolveAnnotations(entitySet.Annotation, name+"/"+entitySet.Name, 0)
+			}
+			for k := range container.Singleton {
+				singleton := &container.Singleton[k]
+				singleton.Type = s.resolveType(singleton.Type, name+"/"+singleton.Name, 0)
+				s.resolveAnnotations(singleton.Annotation, name+"/"+singleton.Name, 0)
+			}
+		}
+		for j := range schema.Annotations {
+			annotations := &schema.Annotations[j]
+			annotations.Target = s.resolveTarget(annotations.Target, schema.Namespace)
+			s.resolveAnnotations(annotations.Annotation, annotations.Target, 0)
+		}
+	}
+}
+
+// resolveTarget resolves the target of an Annotations element, which is a namespace or a model
+// element with an optional action overload and path, i.e. "Org.Action(Org.Type)/Parameter"
+func (s *symbolTable) resolveTarget(target string, referencedBy string) string {
+	name, path, hasPath := strings.Cut(target, "/")
+	name, overload, hasOverload := strings.Cut(name, "(")
+	namespace, ok := s.resolveNamespace(name)
+	if ok && !hasOverload {
+		name = namespace
+	} else {
+		name = s.resolveType(name, referencedBy, 0)
+	}
+	if hasOverload {
+		name += "(" + s.resolveType(strings.TrimSuffix(overload, ")"), referencedBy, 0) + ")"
+	}
+	if hasPath {
+		name += "/" + path
+	}
+	return name
+}
+
+func (s *symbolTable) resolveProperties(properties []Property, typeName string) {
+	for i := range properties {
+		name := typeName + "/" + properties[i].Name
+		properties[i].Type = s.resolveType(properties[i].Type, name, properties[i].Line)
+		s.resolveAnnotations(properties[i].Annotation, name, properties[i].Line)
+	}
+}
+
+func (s *symbolTable) resolveNavigationProperties(navProps []NavigationProperty, typeName string) {
+	for i := range navProps {
+		name := typeName + "/" + navProps[i].Name
+		navProps[i].Type = s.resolveType(navProps[i].Type, name, navProps[i].Line)
+		s.resolveAnnotations(navProps[i].Annotation, name, navProps[i].Line)
+	}
+}
@@ -0,0 +1,82 @@
+package csdl
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestResolveNames(t *testing.T) {
+	parser := NewParser()
+	defer parser.Close()
+	parser.AddFile("Widget_v1.xml", io.NopCloser(strings.NewReader(`<?xml version="1.0" encoding="UTF-8"?>
+<edmx:Edmx xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx" Version="4.0">
+  <edmx:Reference Uri="http://docs.oasis-open.org/odata/odata/v4.0/errata03/csd01/complete/vocabularies/Org.OData.Core.V1.xml">
+    <edmx:Include Namespace="Org.OData.Core.V1" Alias="Core"/>
+  </edmx:Reference>
+  <edmx:Reference Uri="Part_v1.xml">
+    <edmx:Include Namespace="Part.v1_0_0" Alias="P"/>
+  </edmx:Reference>
+  <edmx:DataServices>
+    <Schema xmlns="http://docs.oasis-open.org/odata/ns/edm" Namespace="Widget.v1_0_0" Alias="Self">
+      <ComplexType Name="Base">
+        <Property Name="Id" Type="Edm.String"/>
+      </ComplexType>
+      <ComplexType Name="Widget" BaseType="Self.Base">
+        <Annotation Term="Core.Description" String="A widget."/>
+        <Property Name="Part" Type="P.Part"/>
+        <Property Name="Parts" Type="Collection(P.Part)"/>
+        <Property Name="Missing" Type="Nope.Thing"/>
+        <Property Name="Unqualified" Type="Thing"/>
+      </ComplexType>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`)))
+	parser.AddFile("Part_v1.xml", io.NopCloser(strings.NewReader(`<?xml version="1.0" encoding="UTF-8"?>
+<edmx:Edmx xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx" Version="4.0">
+  <edmx:DataServices>
+    <Schema xmlns="http://docs.oasis-open.org/odata/ns/edm" Namespace="Part.v1_0_0">
+      <ComplexType Name="Part">
+        <Property Name="Name" Type="Edm.String"/>
+      </ComplexType>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`)))
+	types, err := parser.Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	widget := types["Widget.v1_0_0.Widget"]
+	if widget == nil {
+		t.Fatal("Widget wasn't parsed")
+	}
+	if widget.BaseType != "Widget.v1_0_0.Base" {
+		t.Errorf("got BaseType %s, want the schema alias resolved to Widget.v1_0_0.Base", widget.BaseType)
+	}
+	if widget.Description != "A widget." {
+		t.Errorf("got Description %q, want the Core alias resolved to the OData vocabulary", widget.Description)
+	}
+	tests := map[string]string{
+		"Part":        "Part.v1_0_0.Part",
+		"Parts":       "Collection(Part.v1_0_0.Part)",
+		"Missing":     "Nope.Thing",
+		"Unqualified": "Thing",
+	}
+	for name, want := range tests {
+		got := widget.Properties[name].Type
+		if got != want {
+			t.Errorf("%s has type %s, want %s", name, got, want)
+		}
+	}
+	unresolved := map[string]bool{}
+	for _, diagnostic := range parser.Diagnostics {
+		if diagnostic.File != "Widget_v1.xml" || diagnostic.Line == 0 || !strings.HasPrefix(diagnostic.Message, "unresolved name") {
+			t.Errorf("unexpected diagnostic %s", diagnostic)
+			continue
+		}
+		unresolved[diagnostic.Name] = true
+	}
+	if len(unresolved) != 2 || !unresolved["Widget.v1_0_0.Widget/Missing"] || !unresolved["Widget.v1_0_0.Widget/Unqualified"] {
+		t.Errorf("got unresolved names for %v, want Missing and Unqualified", unresolved)
+	}
+}
@@ -0,0 +1,180 @@
+package csdl
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// AnnotationsOf returns the annotations of a model element, both the inline ones and the ones from
+// Annotations elements in the document that target it. The target is a path like
+// "Chassis.v1_0_0.Chassis/ChassisType", "Resource.ResetType/On" or
+// "ComputerSystem.Reset(ComputerSystem.v1_0_0.Actions)/ResetType". The qualifier of an
+// Annotations element is applied to the annotations in it that don't have their own. Targets can
+// use the aliases the document defines. Enum members written as strings, like CSDL JSON does, are
+// EnumMember expressions.
+func (e *Edmx) AnnotationsOf(target string) []Annotation {
+	symbols := newSymbolTable("", e, newVocabulary(map[string]*Edmx{"": e}))
+	target = symbols.resolveTarget(target, "")
+	annotations := []Annotation{}
+	for _, element := range e.targetAnnotations(target) {
+		annotations = append(annotations, *element...)
+	}
+	for _, schema := range e.DataServices.Schema {
+		for _, external := range schema.Annotations {
+			if symbols.resolveTarget(external.Target, "") == target {
+				annotations = append(annotations, external.qualified()...)
+			}
+		}
+	}
+	for i := range annotations {
+		annotations[i].Expr = enumMembers(annotations[i].Expr, symbols.resolveTerm(annotations[i].Term, "", 0), symbols.vocabulary)
+		annotations[i].setConstant()
+	}
+	return annotations
+}
+
+// qualified returns the annotations with the qualifier of the Annotations element
+func (a Annotations) qualified() []Annotation {
+	annotations := slices.Clone(a.Annotation)
+	for i := range annotations {
+		if annotations[i].Qualifier == "" {
+			annotations[i].Qualifier = a.Qualifier
+		}
+	}
+	return annotations
+}
+
+// applyExternalAnnotations moves the annotations of the Annotations elements onto the model
+// elements they target, in any of the documents, so they are read like inline annotations. The
+// Annotations elements that don't target anything in the documents are reported.
+func applyExternalAnnotations(documents map[string]*Edmx) Diagnostics {
+	diagnostics := Diagnostics{}
+	for _, fileName := range slices.Sorted(maps.Keys(documents)) {
+		for i := range documents[fileName].DataServices.Schema {
+			schema := &documents[fileName].DataServices.Schema[i]
+			for _, external := range schema.Annotations {
+				found := false
+				for _, edmx := range documents {
+					for _, element := range edmx.targetAnnotations(external.Target) {
+						*element = append(*element, external.qualified()...)
+						found = true
+					}
+				}
+				if !found {
+					diagnostics.add(Warning, fileName, 0, external.Target, "annotations target a model element that isn't defined, they are left out")
+				}
+			}
+			schema.Annotations = nil
+		}
+	}
+	return diagnostics
+}
+
+// targetAnnotations returns the annotations of the model elements a target path refers to. An
+// action without the binding parameter type in parentheses refers to every overload.
+func (e *Edmx) targetAnnotations(target string) []*[]Annotation {
+	name, path, _ := strings.Cut(target, "/")
+	name, overload, hasOverload := strings.Cut(name, "(")
+	overload = strings.TrimSuffix(overload, ")")
+	elements := []*[]Annotation{}
+	for i := range e.DataServices.Schema {
+		schema := &e.DataServices.Schema[i]
+		if name == schema.Namespace && path == "" && !hasOverload {
+			elements = append(elements, &schema.Annotation)
+			continue
+		}
+		index := strings.LastIndex(name, ".")
+		if index == -1 || name[:index] != schema.Namespace {
+			continue
+		}
+		local := name[index+1:]
+		for j := range schema.EntityType {
+			entityType := &schema.EntityType[j]
+			if entityType.Name == local {
+				elements = append(elements, propertyAnnotations(&entityType.Annotation, entityType.Property, entityType.NavigationProperty, path)...)
+			}
+		}
+		for j := range schema.ComplexType {
+			complexType := &schema.ComplexType[j]
+			if complexType.Name == local {
+				elements = append(elements, propertyAnnotations(&complexType.Annotation, complexType.Property, complexType.NavigationProperty, path)...)
+			}
+		}
+		for j := range schema.EnumType {
+			enumType := &schema.EnumType[j]
+			if enumType.Name != local {
+				continue
+			}
+			if path == "" {
+				elements = append(elements, &enumType.Annotation)
+			}
+			for k := range enumType.Member {
+				if enumType.Member[k].Name == path {
+					elements = append(elements, &enumType.Member[k].Annotation)
+				}
+			}
+		}
+		for j := range schema.TypeDefinition {
+			if schema.TypeDefinition[j].Name == local && path == "" {
+				elements = append(elements, &schema.TypeDefinition[j].Annotation)
+			}
+		}
+		for j := range schema.Action {
+			action := &schema.Action[j]
+			if action.Name != local {
+				continue
+			}
+			if hasOverload && (!action.IsBound || len(action.Parameter) == 0 || action.Parameter[0].Type != overload) {
+				continue
+			}
+			if path == "" {
+				elements = append(elements, &action.Annotation)
+			}
+			for k := range action.Parameter {
+				if action.Parameter[k].Name == path {
+					elements = append(elements, &action.Parameter[k].Annotation)
+				}
+			}
+		}
+		for j := range schema.EntityContainer {
+			container := &schema.EntityContainer[j]
+			if container.Name != local {
+				continue
+			}
+			if path == "" {
+				elements = append(elements, &container.Annotation)
+			}
+			for k := range container.EntitySet {
+				if container.EntitySet[k].Name == path {
+					elements = append(elements, &container.EntitySet[k].Annotation)
+				}
+			}
+			for k := range container.Singleton {
+				if container.Singleton[k].Name == path {
+					elements = append(elements, &container.Singleton[k].Annotation)
+				}
+			}
+		}
+	}
+	return elements
+}
+
+// propertyAnnotations returns the annotations of a structured type, or of its property if there is
+// a path
+func propertyAnnotations(typeAnnotations *[]Annotation, properties []Property, navProps []NavigationProperty, path string) []*[]Annotation {
+	if path == "" {
+		return []*[]Annotation{typeAnnotations}
+	}
+	for i := range properties {
+		if properties[i].Name == path {
+			return []*[]Annotation{&properties[i].Annotation}
+		}
+	}
+	for i := range navProps {
+		if navProps[i].Name == path {
+			return []*[]Annotation{&navProps[i].Annotation}
+		}
+	}
+	return nil
+}
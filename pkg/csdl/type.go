@@ -1,7 +1,6 @@
 package csdl
 
 import (
-	"fmt"
 	"go/ast"
 	"go/token"
 	"maps"
@@ -11,14 +10,37 @@ import (
 )
 
 type Type struct {
-	Name         string
-	Namespace    string
-	BaseType     string
-	Properties   map[string]PropType
-	Members      map[string]MemberType
-	Wildcard     bool              // true if this is a wildcard type, like Attributes
-	Replacements map[string]string // used to replace types with their underlying type
-	ComplexType  bool
+	Name       string
+	Namespace  string
+	BaseType   string
+	Properties map[string]PropType
+	Members    []MemberType // in document order
+	// UnderlyingType and IsFlags are from the EnumType, enums with either one are integers
+	UnderlyingType string
+	IsFlags        bool
+	Wildcard       bool              // true if this is a wildcard type, like Attributes
+	Replacements   map[string]string // used to replace types with their underlying type
+	ComplexType    bool
+	Collection     bool // true if this is a Redfish resource collection
+	Request        bool // true if this is the request body of an action
+	Actions        []ActionType
+	// From the Description and LongDescription annotations, used for doc comments
+	Description     string
+	LongDescription string
+	Abstract        bool
+	// Deprecated is the "Deprecated:" paragraph for the doc comment, see deprecation
+	Deprecated string
+	// Uris are the URIs of a resource, from Redfish.Uris or the service's singletons
+	Uris []string
+	// Handlers generates the server interfaces and routes for the Uris
+	Handlers bool
+	// StrictEnums generates an UnmarshalJSON for string enums that rejects values that aren't members
+	StrictEnums bool
+	// FieldVersions generates the version of the schema each property was added in
+	FieldVersions bool
+	// Where the type is defined, for diagnostics
+	File string
+	Line int
 }
 
 func NewTypeFromEntityType(entityType EntityType, nameSpace string) *Type {
@@ -28,34 +50,75 @@ func NewTypeFromEntityType(entityType EntityType, nameSpace string) *Type {
 		Properties:  make(map[string]PropType),
 		BaseType:    entityType.BaseType,
 		ComplexType: false,
+		Collection:  isResourceCollection(entityType),
+		Abstract:    entityType.Abstract,
+		Line:        entityType.Line,
 	}
+	myType.Description, myType.LongDescription = descriptions(entityType.Annotation)
+	myType.Deprecated = deprecation(entityType.Annotation)
+	myType.Uris = annotationStrings(entityType.Annotation, urisTerm)
 	for _, property := range entityType.Property {
-		canBeNull := true
-		if property.Nullable != nil {
-			canBeNull = *property.Nullable
-		}
-		propType := PropType{
-			Navigation: false,
-			Type:       property.Type,
-			CanBeNull:  canBeNull,
-		}
-		myType.Properties[property.Name] = propType
+		myType.Properties[property.Name] = newPropType(property)
 	}
 	for _, navProp := range entityType.NavigationProperty {
-		canBeNull := true
-		if navProp.Nullable != nil {
-			canBeNull = *navProp.Nullable
-		}
-		propType := PropType{
-			Navigation: true,
-			Type:       navProp.Type,
-			CanBeNull:  canBeNull,
-		}
-		myType.Properties[navProp.Name] = propType
+		myType.Properties[navProp.Name] = newNavPropType(navProp)
+	}
+	if myType.Collection {
+		// These are OData control annotations on the Members property, so they aren't in the CSDL
+		myType.Properties["MembersCount"] = PropType{Type: "Edm.Int64", CanBeNull: false, Navigation: false, JsonName: "Members@odata.count"}
+		myType.Properties["MembersNextLink"] = PropType{Type: "Edm.String", CanBeNull: true, Navigation: false, JsonName: "Members@odata.nextLink"}
 	}
 	return myType
 }
 
+// isResourceCollection returns true if the entity type is a Redfish collection, i.e. it derives
+// from Resource.ResourceCollection but isn't one of the abstract base types
+func isResourceCollection(entityType EntityType) bool {
+	if entityType.Name == "ResourceCollection" {
+		return false
+	}
+	return strings.HasSuffix(entityType.BaseType, ".ResourceCollection")
+}
+
+func newPropType(property Property) PropType {
+	canBeNull := true
+	if property.Nullable != nil {
+		canBeNull = *property.Nullable
+	}
+	propType := PropType{
+		Navigation: false,
+		Type:       property.Type,
+		CanBeNull:  canBeNull,
+		Line:       property.Line,
+	}
+	propType.Description, propType.LongDescription = descriptions(property.Annotation)
+	propType.Deprecated = deprecation(property.Annotation)
+	propType.Pattern, propType.Minimum, propType.Maximum = validations(property.Annotation)
+	propType.Permissions = permissions(property.Annotation)
+	propType.Required = hasTag(property.Annotation, requiredTerm)
+	propType.RequiredOnCreate = hasTag(property.Annotation, requiredOnCreateTerm)
+	return propType
+}
+
+func newNavPropType(navProp NavigationProperty) PropType {
+	canBeNull := true
+	if navProp.Nullable != nil {
+		canBeNull = *navProp.Nullable
+	}
+	propType := PropType{
+		Navigation: true,
+		Type:       navProp.Type,
+		CanBeNull:  canBeNull,
+		Line:       navProp.Line,
+	}
+	propType.Description, propType.LongDescription = descriptions(navProp.Annotation)
+	propType.Deprecated = deprecation(navProp.Annotation)
+	propType.Permissions = permissions(navProp.Annotation)
+	propType.Required = hasTag(navProp.Annotation, requiredTerm)
+	propType.RequiredOnCreate = hasTag(navProp.Annotation, requiredOnCreateTerm)
+	return propType
+}
+
 func NewTypeFromComplexType(complexType ComplexType, nameSpace string) *Type {
 	myType := &Type{
 		Name:        complexType.Name,
@@ -63,30 +126,16 @@ func NewTypeFromComplexType(complexType ComplexType, nameSpace string) *Type {
 		Properties:  make(map[string]PropType),
 		BaseType:    complexType.BaseType,
 		ComplexType: true,
+		Abstract:    complexType.Abstract,
+		Line:        complexType.Line,
 	}
+	myType.Description, myType.LongDescription = descriptions(complexType.Annotation)
+	myType.Deprecated = deprecation(complexType.Annotation)
 	for _, property := range complexType.Property {
-		canBeNull := true
-		if property.Nullable != nil {
-			canBeNull = *property.Nullable
-		}
-		propType := PropType{
-			Navigation: false,
-			Type:       property.Type,
-			CanBeNull:  canBeNull,
-		}
-		myType.Properties[property.Name] = propType
+		myType.Properties[property.Name] = newPropType(property)
 	}
 	for _, navProp := range complexType.NavigationProperty {
-		canBeNull := true
-		if navProp.Nullable != nil {
-			canBeNull = *navProp.Nullable
-		}
-		propType := PropType{
-			Navigation: true,
-			Type:       navProp.Type,
-			CanBeNull:  canBeNull,
-		}
-		myType.Properties[navProp.Name] = propType
+		myType.Properties[navProp.Name] = newNavPropType(navProp)
 	}
 	if len(myType.Properties) == 0 {
 		// Search for annotations that inidicate this is a wildcard type
@@ -95,7 +144,7 @@ func NewTypeFromComplexType(complexType ComplexType, nameSpace string) *Type {
 				myType.Wildcard = true
 				break
 			}
-			if annotation.Term == "OData.AdditionalProperties" && annotation.Bool {
+			if annotation.Term == "OData.AdditionalProperties" && annotationBool(annotation) {
 				myType.Wildcard = true
 				break
 			}
@@ -104,20 +153,62 @@ func NewTypeFromComplexType(complexType ComplexType, nameSpace string) *Type {
 	return myType
 }
 
+// NewTypeFromAction creates the request body type for an action, the binding parameter of
+// bound actions is not part of the body so it is skipped
+func NewTypeFromAction(action Action, nameSpace string) *Type {
+	myType := &Type{
+		Name:        action.Name + "Request",
+		Namespace:   nameSpace,
+		Properties:  make(map[string]PropType),
+		ComplexType: true,
+		Request:     true,
+		Line:        action.Line,
+	}
+	myType.Description, myType.LongDescription = descriptions(action.Annotation)
+	parameters := action.Parameter
+	if action.IsBound && len(parameters) > 0 {
+		parameters = parameters[1:]
+	}
+	for _, parameter := range parameters {
+		canBeNull := true
+		if parameter.Nullable != nil {
+			canBeNull = *parameter.Nullable
+		}
+		propType := PropType{
+			Navigation: false,
+			Type:       parameter.Type,
+			CanBeNull:  canBeNull,
+			Line:       parameter.Line,
+		}
+		propType.Description, propType.LongDescription = descriptions(parameter.Annotation)
+		propType.Pattern, propType.Minimum, propType.Maximum = validations(parameter.Annotation)
+		myType.Properties[parameter.Name] = propType
+	}
+	return myType
+}
+
 func NewTypeFromEnumType(enumType EnumType, nameSpace string) *Type {
 	myType := &Type{
 		Name:      enumType.Name,
 		Namespace: nameSpace,
-		Members:   make(map[string]MemberType),
-	}
+		Members:   make([]MemberType, 0, len(enumType.Member)),
+		Line:      enumType.Line,
+		// Redfish enums have neither so they stay strings
+		UnderlyingType: enumType.UnderlyingType,
+		IsFlags:        enumType.IsFlags,
+	}
+	myType.Description, myType.LongDescription = descriptions(enumType.Annotation)
+	myType.Deprecated = deprecation(enumType.Annotation)
 	for _, member := range enumType.Member {
 		memType := MemberType{
 			Name: member.Name,
 		}
+		memType.Description, memType.LongDescription = descriptions(member.Annotation)
+		memType.Deprecated = deprecation(member.Annotation)
 		if member.Value != nil {
 			memType.Value = *member.Value
 		}
-		myType.Members[member.Name] = memType
+		myType.Members = append(myType.Members, memType)
 	}
 	return myType
 }
@@ -132,6 +223,11 @@ func handleUnknownType(t *Type, types map[string]*Type) *Type {
 		t.Properties["Name"] = PropType{Type: "Edm.String", CanBeNull: false, Navigation: false}
 		t.Properties["Description"] = PropType{Type: "Edm.String", CanBeNull: true, Navigation: false}
 		return t
+	case "Resource.v1_0_0.ResourceCollection":
+		t.Properties["Name"] = PropType{Type: "Edm.String", CanBeNull: false, Navigation: false}
+		t.Properties["Description"] = PropType{Type: "Edm.String", CanBeNull: true, Navigation: false}
+		t.Properties["Oem"] = PropType{Type: "Resource.Oem", CanBeNull: true, Navigation: false}
+		return t
 	case "Resource.Links":
 		t.Properties["Oem"] = PropType{Type: "Resource.Oem", CanBeNull: true, Navigation: false}
 		return t
@@ -146,6 +242,7 @@ func handleUnknownType(t *Type, types map[string]*Type) *Type {
 }
 
 func (t *Type) Fold(types map[string]*Type, replacements map[string]string) *Type {
+	t.Replacements = replacements
 	baseType, ok := types[t.BaseType]
 	if !ok {
 		replacement, ok := replacements[t.BaseType]
@@ -165,39 +262,95 @@ func (t *Type) Fold(types map[string]*Type, replacements map[string]string) *Typ
 		}
 		t.Properties[name] = prop
 	}
+	// Other versions of the type document it, bases from other schemas like Resource describe
+	// something else
+	if unversionedNamespace(baseType.Namespace) == unversionedNamespace(t.Namespace) {
+		if t.Description == "" {
+			t.Description, t.LongDescription = baseType.Description, baseType.LongDescription
+		}
+		if t.Deprecated == "" {
+			t.Deprecated = baseType.Deprecated
+		}
+		if len(t.Uris) == 0 {
+			t.Uris = baseType.Uris
+		}
+	}
+	for _, action := range baseType.Actions {
+		if !slices.ContainsFunc(t.Actions, func(a ActionType) bool { return a.Name == action.Name }) {
+			t.Actions = append(t.Actions, action)
+		}
+	}
 	t.BaseType = baseType.BaseType
 	if t.BaseType != "" {
 		t = t.Fold(types, replacements)
 	}
-	if t != nil {
-		t.Replacements = replacements
-	}
 	return t
 }
 
 func (t *Type) Node(types map[string]*Type) []ast.Node {
-	if len(t.Properties) != 0 {
-		// This is a struct
-		return t.structNode(types)
+	structType, ok := t.structType(types)
+	if ok {
+		return structType.structNode(types)
 	}
 	if len(t.Members) != 0 {
 		// This is an enum
 		return t.enumNode(types)
 	}
-	switch {
-	case strings.HasSuffix(t.Name, "OemActions") || t.Name == "ItemOrCollection" || t.Wildcard:
-		// Just skip this type, it's an empty complex type
-		return []ast.Node{}
-	}
-	if !strings.Contains(t.Namespace, ".") {
-		// We should check for other versions of this type...
-		for name, typeData := range types {
-			if strings.HasPrefix(name, t.Namespace) && t.Name == typeData.Name && len(typeData.Properties) > 0 {
-				return typeData.structNode(types)
+	// Nothing to generate, Check reports this unless the type is abstract
+	return []ast.Node{}
+}
+
+// structType returns the type that t is generated as a struct from, which is the newest version for
+// an unversioned type, false if t isn't generated as a struct
+func (t *Type) structType(types map[string]*Type) (*Type, bool) {
+	if t.isStruct() {
+		return t, true
+	}
+	if len(t.Members) != 0 || t.skipped() {
+		return nil, false
+	}
+	return t.newestVersion(types)
+}
+
+// skipped returns true for the empty complex types that are handled by the types that use them
+func (t *Type) skipped() bool {
+	return strings.HasSuffix(t.Name, "OemActions") || t.Name == "ItemOrCollection" || t.Wildcard
+}
+
+// newestVersion returns the newest versioned definition of an unversioned type
+func (t *Type) newestVersion(types map[string]*Type) (*Type, bool) {
+	if strings.Contains(t.Namespace, ".") {
+		return nil, false
+	}
+	newest := ""
+	for name, typeData := range types {
+		if strings.HasPrefix(name, t.Namespace+".") && t.Name == typeData.Name && len(typeData.Properties) > 0 {
+			if newest == "" || sortNamespace(name, newest) > 0 {
+				newest = name
 			}
 		}
 	}
-	panic("unknown type: " + fmt.Sprintf("%#v", t))
+	if newest == "" {
+		return nil, false
+	}
+	return types[newest], true
+}
+
+// setFile records the document the type and its properties are defined in, along with the
+// namespace of the properties
+func (t *Type) setFile(file string) *Type {
+	t.File = file
+	for name, prop := range t.Properties {
+		prop.File = file
+		prop.Namespace = t.Namespace
+		t.Properties[name] = prop
+	}
+	return t
+}
+
+// isStruct returns true if the type is generated as a struct
+func (t *Type) isStruct() bool {
+	return len(t.Properties) != 0 || len(t.Actions) != 0 || t.Request
 }
 
 func (t *Type) structNode(types map[string]*Type) []ast.Node {
@@ -207,6 +360,8 @@ func (t *Type) structNode(types map[string]*Type) []ast.Node {
 		},
 	}
 	// do these in order...
+	first := []string{}
+	props := []propField{}
 	if !t.ComplexType {
 		// Redfish uses ComplexType for types that are not individually addressable,
 		// so skip the ID fields in these types
@@ -219,24 +374,29 @@ func (t *Type) structNode(types map[string]*Type) []ast.Node {
 		if ok {
 			field := idProp.ToField("Id", types, t.Replacements)
 			structType.Fields.List = append(structType.Fields.List, field)
-			delete(t.Properties, "Id")
+			first = append(first, "Id")
+			props = append(props, propField{Name: "Id", Prop: idProp, Field: field})
 		}
 	}
-	structType.Fields.List = append(structType.Fields.List, &ast.Field{
-		Names: []*ast.Ident{ast.NewIdent("Type")},
-		Type:  &ast.Ident{Name: "string"},
-		Tag:   &ast.BasicLit{Kind: token.STRING, Value: "`json:\"@odata.type,omitempty\"`"},
-	},
-		&ast.Field{
-			Names: []*ast.Ident{ast.NewIdent("Context")},
+	if !t.Request {
+		// Action request bodies are not OData resources, so they don't get the control information
+		structType.Fields.List = append(structType.Fields.List, &ast.Field{
+			Names: []*ast.Ident{ast.NewIdent("Type")},
 			Type:  &ast.Ident{Name: "string"},
-			Tag:   &ast.BasicLit{Kind: token.STRING, Value: "`json:\"@odata.context,omitempty\"`"},
-		})
+			Tag:   &ast.BasicLit{Kind: token.STRING, Value: "`json:\"@odata.type,omitempty\"`"},
+		},
+			&ast.Field{
+				Names: []*ast.Ident{ast.NewIdent("Context")},
+				Type:  &ast.Ident{Name: "string"},
+				Tag:   &ast.BasicLit{Kind: token.STRING, Value: "`json:\"@odata.context,omitempty\"`"},
+			})
+	}
 	nameProp, ok := t.Properties["Name"]
 	if ok {
 		field := nameProp.ToField("Name", types, t.Replacements)
 		structType.Fields.List = append(structType.Fields.List, field)
-		delete(t.Properties, "Name")
+		first = append(first, "Name")
+		props = append(props, propField{Name: "Name", Prop: nameProp, Field: field})
 	}
 	descriptionProp, ok := t.Properties["Description"]
 	if ok {
@@ -246,15 +406,30 @@ func (t *Type) structNode(types map[string]*Type) []ast.Node {
 			Value: "`json:\",omitempty\"`",
 		}
 		structType.Fields.List = append(structType.Fields.List, field)
-		delete(t.Properties, "Description")
+		first = append(first, "Description")
+		props = append(props, propField{Name: "Description", Prop: descriptionProp, Field: field})
 	}
 	fieldNames := slices.Sorted(maps.Keys(t.Properties))
 	for _, name := range fieldNames {
+		if slices.Contains(first, name) {
+			// Already added above, don't remove it from the type since other files may need it
+			continue
+		}
 		prop := t.Properties[name]
 		field := prop.ToField(name, types, t.Replacements)
 		structType.Fields.List = append(structType.Fields.List, field)
+		props = append(props, propField{Name: name, Prop: prop, Field: field})
+	}
+	for _, action := range t.Actions {
+		structType.Fields.List = append(structType.Fields.List, &ast.Field{
+			Doc:   docComment(action.Description, ""),
+			Names: []*ast.Ident{ast.NewIdent(action.Name)},
+			Type:  &ast.Ident{Name: "*Action"},
+			Tag:   jsonTag(action.JsonName(), true),
+		})
 	}
 	ret := &ast.GenDecl{
+		Doc: docComment(t.Description, t.LongDescription, t.Deprecated),
 		Tok: token.TYPE,
 		Specs: []ast.Spec{
 			&ast.TypeSpec{
@@ -263,17 +438,64 @@ func (t *Type) structNode(types map[string]*Type) []ast.Node {
 			},
 		},
 	}
-	return []ast.Node{ret}
+	nodes := []ast.Node{ret}
+	nodes = append(nodes, t.validateNodes(types, props)...)
+	nodes = append(nodes, t.patchNodes(types, props)...)
+	nodes = append(nodes, t.createNodes(types, props)...)
+	nodes = append(nodes, t.fieldVersionsNodes(types)...)
+	nodes = append(nodes, t.uriNodes()...)
+	return append(nodes, t.handlerNodes(types)...)
+}
+
+// enumGoTypes are the Go types of the integer types an enum can have as its underlying type
+var enumGoTypes = map[string]string{
+	"Edm.Byte":  "uint8",
+	"Edm.SByte": "int8",
+	"Edm.Int16": "int16",
+	"Edm.Int32": "int32",
+	"Edm.Int64": "int64",
+}
+
+// integerEnum returns true if the enum has an underlying type, is flags, or gives its members
+// values. Redfish enums do none of those and are strings, since that is how enums are serialized.
+func (t *Type) integerEnum() bool {
+	if t.UnderlyingType != "" || t.IsFlags {
+		return true
+	}
+	return slices.ContainsFunc(t.Members, func(member MemberType) bool {
+		return member.Value != ""
+	})
 }
 
 func (t *Type) underLyingEnumType() string {
-	// TODO handle integer backed enums
-	return "string"
+	if !t.integerEnum() {
+		return "string"
+	}
+	goType, ok := enumGoTypes[t.UnderlyingType]
+	if !ok {
+		// Edm.Int32 is the default, Check reports the types that aren't integers
+		return "int32"
+	}
+	return goType
+}
+
+// memberValue returns the value of the i'th member of an integer enum. Members without a value are
+// numbered by position, or are the bit for their position in a flags enum.
+func (t *Type) memberValue(i int) string {
+	value, err := strconv.ParseInt(t.Members[i].Value, 10, 64)
+	if err == nil {
+		return strconv.FormatInt(value, 10)
+	}
+	if t.IsFlags {
+		return "1 << " + strconv.Itoa(i)
+	}
+	return strconv.Itoa(i)
 }
 
 func (t *Type) enumNode(map[string]*Type) []ast.Node {
 	ret := []ast.Node{
 		&ast.GenDecl{
+			Doc: docComment(t.Description, t.LongDescription, t.Deprecated),
 			Tok: token.TYPE,
 			Specs: []ast.Spec{
 				&ast.TypeSpec{
@@ -285,18 +507,23 @@ func (t *Type) enumNode(map[string]*Type) []ast.Node {
 			},
 		},
 	}
+	integer := t.integerEnum()
 	constNode := &ast.GenDecl{
 		Tok:   token.CONST,
 		Specs: make([]ast.Spec, 0, len(t.Members)),
 	}
-	for _, member := range t.Members {
+	for i, member := range t.Members {
 		valueSpec := &ast.ValueSpec{
+			Doc:   docComment(member.Description, member.LongDescription, member.Deprecated),
 			Names: []*ast.Ident{ast.NewIdent(t.GoTypeName() + "_" + member.Name)},
 			Type:  &ast.Ident{Name: t.GoTypeName()},
 		}
-		if member.Value != "" {
+		if integer {
 			valueSpec.Values = []ast.Expr{
-				ast.NewIdent(member.Value),
+				&ast.BasicLit{
+					Kind:  token.INT,
+					Value: t.memberValue(i),
+				},
 			}
 		} else {
 			valueSpec.Values = []ast.Expr{
@@ -308,34 +535,213 @@ func (t *Type) enumNode(map[string]*Type) []ast.Node {
 		}
 		constNode.Specs = append(constNode.Specs, valueSpec)
 	}
-	return append(ret, constNode)
+	ret = append(ret, constNode)
+	if integer {
+		ret = append(ret, t.enumMethodsNode())
+	} else {
+		ret = append(ret, t.stringEnumMethodsNode())
+	}
+	return ret
 }
 
-func (t *Type) GoTypeName() string {
-	if strings.HasPrefix(t.Namespace, t.Name) {
-		return t.Name
+// stringEnumMethodsNode generates the methods of a string enum, along with an UnmarshalJSON that
+// rejects values that aren't members for StrictEnums
+func (t *Type) stringEnumMethodsNode() ast.Node {
+	name := t.GoTypeName()
+	src := &strings.Builder{}
+	src.WriteString(`
+var values` + name + ` = []` + name + `{
+`)
+	for _, member := range t.Members {
+		src.WriteString(name + `_` + member.Name + `,
+`)
 	}
+	src.WriteString(`}
+
+// String returns the value of the member
+func (e ` + name + `) String() string {
+	return string(e)
+}
+
+// Values returns the members of ` + name + ` in schema order
+func (` + name + `) Values() []` + name + ` {
+	return append([]` + name + `(nil), values` + name + `...)
+}
+
+// IsValid returns true if e is one of the members
+func (e ` + name + `) IsValid() bool {
+	return isEnumValue(values` + name + `, e)
+}
+
+// Parse` + name + ` returns the member with the value, it is an error if there isn't one
+func Parse` + name + `(text string) (` + name + `, error) {
+	return parseEnum(values` + name + `, text)
+}
+`)
+	if t.StrictEnums {
+		src.WriteString(`
+// UnmarshalJSON sets e to the member with the value, it is an error if there isn't one
+func (e *` + name + `) UnmarshalJSON(b []byte) error {
+	return unmarshalStrictEnum(values` + name + `, e, b)
+}
+`)
+	}
+	return &sourceNode{src: src.String()}
+}
+
+// enumMethodsNode generates the methods of an integer enum, which is serialized by member name like
+// a string enum. Flags are serialized as the names of the flags that are set separated by commas.
+// Unmarshaling always rejects names that aren't members, so StrictEnums doesn't change anything.
+func (t *Type) enumMethodsNode() ast.Node {
+	name := t.GoTypeName()
+	src := &strings.Builder{}
+	src.WriteString(`
+var members` + name + ` = []enumMember[` + name + `]{
+`)
+	for _, member := range t.Members {
+		src.WriteString(`{` + strconv.Quote(member.Name) + `, ` + name + `_` + member.Name + `},
+`)
+	}
+	src.WriteString(`}
+
+// Values returns the members of ` + name + ` in schema order
+func (` + name + `) Values() []` + name + ` {
+	return enumValues(members` + name + `)
+}
+`)
+	if !t.IsFlags {
+		src.WriteString(`
+// IsValid returns true if e is one of the members
+func (e ` + name + `) IsValid() bool {
+	return isEnumMember(members` + name + `, e)
+}
+
+// Parse` + name + ` returns the member with the name, it is an error if there isn't one
+func Parse` + name + `(text string) (` + name + `, error) {
+	var e ` + name + `
+	err := e.UnmarshalText([]byte(text))
+	return e, err
+}
+
+// String returns the name of the member, or the number if it isn't one
+func (e ` + name + `) String() string {
+	return enumString(members` + name + `, e)
+}
+
+// MarshalText returns the name of the member, it is an error if it isn't one
+func (e ` + name + `) MarshalText() ([]byte, error) {
+	return marshalEnum(members` + name + `, e)
+}
+
+// UnmarshalText sets e to the member with the name
+func (e *` + name + `) UnmarshalText(text []byte) error {
+	return unmarshalEnum(members` + name + `, e, text)
+}
+`)
+		return &sourceNode{src: src.String()}
+	}
+	src.WriteString(`
+// IsValid returns true if only the bits of flags are set
+func (e ` + name + `) IsValid() bool {
+	return validFlags(members` + name + `, e)
+}
+
+// Parse` + name + ` returns the flags named in the comma separated list, it is an error if one isn't
+// a flag
+func Parse` + name + `(text string) (` + name + `, error) {
+	var e ` + name + `
+	err := e.UnmarshalText([]byte(text))
+	return e, err
+}
+
+// Has returns true if all of the flags are set
+func (e ` + name + `) Has(flags ` + name + `) bool {
+	return e&flags == flags
+}
+
+// Set sets the flags
+func (e *` + name + `) Set(flags ` + name + `) {
+	*e |= flags
+}
+
+// Clear clears the flags
+func (e *` + name + `) Clear(flags ` + name + `) {
+	*e &^= flags
+}
+
+// String returns the names of the flags that are set joined by commas, followed by the number for
+// any bits that aren't a flag
+func (e ` + name + `) String() string {
+	return flagsString(members` + name + `, e)
+}
+
+// MarshalText returns the names of the flags that are set joined by commas, it is an error if a bit
+// that isn't a flag is set
+func (e ` + name + `) MarshalText() ([]byte, error) {
+	return marshalFlags(members` + name + `, e)
+}
+
+// UnmarshalText sets e to the flags named in the comma separated list
+func (e *` + name + `) UnmarshalText(text []byte) error {
+	return unmarshalFlags(members` + name + `, e, text)
+}
+`)
+	return &sourceNode{src: src.String()}
+}
+
+func (t *Type) GoTypeName() string {
 	nameSpace := t.Namespace
 	index := strings.Index(nameSpace, ".")
 	if index != -1 {
 		nameSpace = nameSpace[:index]
 	}
+	if nameSpace == t.Name {
+		return t.Name
+	}
 	return strings.ReplaceAll(nameSpace+"_"+t.Name, ".", "_")
 }
 
 type PropType struct {
-	Navigation bool
-	Type       string
-	CanBeNull  bool
-	JsonName   string
+	Navigation      bool
+	Type            string
+	CanBeNull       bool
+	JsonName        string
+	Description     string
+	LongDescription string
+	Permissions     string // Read, ReadWrite, Write or None, empty if not annotated
+	Deprecated      string
+	// From Redfish.Required and Redfish.RequiredOnCreate
+	Required         bool
+	RequiredOnCreate bool
+	// From the Validation annotations
+	Pattern string
+	Minimum *float64
+	Maximum *float64
+	// TypedLink generates a navigation property as Link[T] rather than OdataID
+	TypedLink bool
+	// Where the property is defined, which may not be the type it ends up in after Fold
+	File      string
+	Line      int
+	Namespace string
 }
 
 func (p *PropType) ToField(name string, types map[string]*Type, replacements map[string]string) *ast.Field {
+	field := p.field(name, types, replacements)
+	field.Doc = docComment(p.Description, p.LongDescription, p.Deprecated)
+	return field
+}
+
+func (p *PropType) field(name string, types map[string]*Type, replacements map[string]string) *ast.Field {
 	switch name {
 	case "Actions":
+		actionsType := "map[string]Action"
+		typeData, ok := doTypeSearch(p.Type, types)
+		if ok && len(typeData.Actions) != 0 {
+			actionsType = "*" + typeData.GoTypeName()
+		}
 		return &ast.Field{
 			Names: []*ast.Ident{ast.NewIdent("Actions")},
-			Type:  &ast.Ident{Name: "map[string]Action"},
+			Type:  &ast.Ident{Name: actionsType},
 			Tag: &ast.BasicLit{
 				Kind:  token.STRING,
 				Value: "`json:\",omitempty\"`",
@@ -359,34 +765,51 @@ func (p *PropType) ToField(name string, types map[string]*Type, replacements map
 		Names: []*ast.Ident{ast.NewIdent(name)},
 		Type:  p.Node(types).(ast.Expr),
 	}
-	if p.JsonName != "" {
-		field.Tag = &ast.BasicLit{
-			Kind:  token.STRING,
-			Value: "`json:\"" + p.JsonName + "\"`",
-		}
-	}
-	if p.Navigation || p.CanBeNull {
-		field.Tag = &ast.BasicLit{
-			Kind:  token.STRING,
-			Value: "`json:\",omitempty\"`",
-		}
-	}
+	omitEmpty := p.Navigation || p.CanBeNull
 	ident, ok := field.Type.(*ast.Ident)
 	if ok && ident.Name == "any" {
-		field.Tag = &ast.BasicLit{
-			Kind:  token.STRING,
-			Value: "`json:\",omitempty\"`",
-		}
+		omitEmpty = true
+	}
+	if p.Required {
+		// Services must always return these, even if they are null
+		omitEmpty = false
+	}
+	if p.JsonName != "" || omitEmpty {
+		field.Tag = jsonTag(p.JsonName, omitEmpty)
 	}
 	return field
 }
 
+func jsonTag(jsonName string, omitEmpty bool) *ast.BasicLit {
+	if omitEmpty {
+		jsonName += ",omitempty"
+	}
+	return &ast.BasicLit{
+		Kind:  token.STRING,
+		Value: "`json:\"" + jsonName + "\"`",
+	}
+}
+
 func (p *PropType) Node(types map[string]*Type) ast.Node {
+	expr, _ := p.expr(types)
+	return expr
+}
+
+// expr returns the Go type of the property, or any and false if the type is unknown
+func (p *PropType) expr(types map[string]*Type) (ast.Expr, bool) {
 	if p.Navigation {
+		link := "OdataID"
+		target, ok := linkTarget(strings.TrimSuffix(strings.TrimPrefix(p.Type, "Collection("), ")"), types)
+		if p.TypedLink && ok {
+			link = "Link[" + target + "]"
+		}
+		if strings.HasPrefix(p.Type, "Collection(") {
+			return &ast.Ident{Name: "[]" + link}, true
+		}
 		if p.CanBeNull {
-			return &ast.Ident{Name: "*OdataID"}
+			return &ast.Ident{Name: "*" + link}, true
 		}
-		return &ast.Ident{Name: "OdataID"}
+		return &ast.Ident{Name: link}, true
 	}
 	typeName := p.Type
 	prefix := ""
@@ -399,59 +822,83 @@ func (p *PropType) Node(types map[string]*Type) ast.Node {
 		prefix = "*"
 	}
 	if strings.HasSuffix(typeName, "OemActions") {
-		return &ast.Ident{Name: "map[string]any"}
+		return &ast.Ident{Name: "map[string]any"}, true
 	}
 	switch typeName {
 	case "Edm.Boolean":
-		return &ast.Ident{Name: prefix + "bool"}
+		return &ast.Ident{Name: prefix + "bool"}, true
 	case "Edm.Byte":
-		return &ast.Ident{Name: prefix + "byte"}
+		return &ast.Ident{Name: prefix + "byte"}, true
 	case "Edm.Date":
-		return &ast.Ident{Name: prefix + "Date"}
+		return &ast.Ident{Name: prefix + "Date"}, true
 	case "Edm.DateTimeOffset":
-		return &ast.Ident{Name: prefix + "DateTimeOffset"}
+		return &ast.Ident{Name: prefix + "DateTimeOffset"}, true
 	case "Edm.Decimal":
-		return &ast.Ident{Name: prefix + "float64"}
+		return &ast.Ident{Name: prefix + "float64"}, true
 	case "Edm.Double":
-		return &ast.Ident{Name: prefix + "float64"}
+		return &ast.Ident{Name: prefix + "float64"}, true
 	case "Edm.Duration":
-		return &ast.Ident{Name: prefix + "Duration"}
+		return &ast.Ident{Name: prefix + "Duration"}, true
+	case "Edm.TimeOfDay":
+		return &ast.Ident{Name: prefix + "TimeOfDay"}, true
 	case "Edm.Int16":
-		return &ast.Ident{Name: prefix + "int16"}
+		return &ast.Ident{Name: prefix + "int16"}, true
 	case "Edm.Int32":
-		return &ast.Ident{Name: prefix + "int32"}
+		return &ast.Ident{Name: prefix + "int32"}, true
 	case "Edm.Int64":
-		return &ast.Ident{Name: prefix + "int64"}
+		return &ast.Ident{Name: prefix + "int64"}, true
 	case "Edm.PrimitiveType":
-		return &ast.Ident{Name: prefix + "any"}
+		return &ast.Ident{Name: prefix + "any"}, true
 	case "Edm.SByte":
-		return &ast.Ident{Name: prefix + "int8"}
+		return &ast.Ident{Name: prefix + "int8"}, true
 	case "Edm.Single":
-		return &ast.Ident{Name: prefix + "float32"}
+		return &ast.Ident{Name: prefix + "float32"}, true
 	case "Edm.String":
-		return &ast.Ident{Name: prefix + "string"}
+		return &ast.Ident{Name: prefix + "string"}, true
 	case "Edm.Guid":
 		fallthrough
 	case "Resource.UUID":
-		return &ast.Ident{Name: prefix + "UUID"}
+		return &ast.Ident{Name: prefix + "UUID"}, true
 	case "Resource.Oem":
-		return &ast.Ident{Name: "map[string]any"}
+		return &ast.Ident{Name: "map[string]any"}, true
 	case "Resource.Description":
-		return &ast.Ident{Name: prefix + "string"}
+		return &ast.Ident{Name: prefix + "string"}, true
 	case "Resource.Name":
-		return &ast.Ident{Name: prefix + "string"}
+		return &ast.Ident{Name: prefix + "string"}, true
 	case "Resource.Status":
-		return &ast.Ident{Name: "Resource_Status"}
+		return &ast.Ident{Name: "Resource_Status"}, true
 	case "Resource.PowerState":
-		return &ast.Ident{Name: "Resource_PowerState"}
+		return &ast.Ident{Name: "Resource_PowerState"}, true
 	default:
 		typeData, ok := doTypeSearch(typeName, types)
 		if !ok {
-			fmt.Printf("Unknown type: %s\n", typeName)
-			return &ast.Ident{Name: prefix + "any"}
+			return &ast.Ident{Name: prefix + "any"}, false
 		}
-		return &ast.Ident{Name: prefix + typeData.GoTypeName()}
+		return &ast.Ident{Name: prefix + typeData.GoTypeName()}, true
+	}
+}
+
+// linkTarget returns the Go type a navigation property to typeName can be followed to, resources
+// that nothing is generated for, like abstract base types, can't be
+func linkTarget(typeName string, types map[string]*Type) (string, bool) {
+	typeData, ok := generatedStruct(typeName, types)
+	if !ok {
+		return "", false
+	}
+	return typeData.GoTypeName(), true
+}
+
+// generatedStruct returns the type a struct is generated from for typeName, which is the newest
+// version for unversioned types
+func generatedStruct(typeName string, types map[string]*Type) (*Type, bool) {
+	typeData, ok := doTypeSearch(typeName, types)
+	if !ok {
+		return nil, false
 	}
+	if typeData.isStruct() {
+		return typeData, true
+	}
+	return typeData.newestVersion(types)
 }
 
 func doTypeSearch(typeName string, types map[string]*Type) (*Type, bool) {
@@ -465,19 +912,54 @@ func doTypeSearch(typeName string, types map[string]*Type) (*Type, bool) {
 		prefix, name, _ = splitNamespace(typeName)
 	}
 	// This may have been folded into a a newer version, go and find it...
-	for mapName, t := range types {
+	// Use the newest version so that the answer doesn't depend on map order
+	found := ""
+	for mapName := range types {
 		myPrefix, _, myName := splitNamespace(mapName)
 		if myName == "" {
 			myPrefix, myName, _ = splitNamespace(mapName)
 		}
 		if myPrefix == prefix && myName == name {
-			return t, true
+			if found == "" || sortNamespace(mapName, found) > 0 {
+				found = mapName
+			}
 		}
 	}
-	return nil, false
+	if found == "" {
+		return nil, false
+	}
+	return types[found], true
+}
+
+// ActionType is an action bound to an Actions complex type
+type ActionType struct {
+	Name        string
+	Namespace   string
+	Description string
+}
+
+// JsonName returns the property name used for the action in the Actions object, i.e. "#ComputerSystem.Reset"
+func (a *ActionType) JsonName() string {
+	return "#" + unversionedNamespace(a.Namespace) + "." + a.Name
+}
+
+// unversionedNamespace strips the version (i.e. v1_0_0) from a namespace if it has one
+func unversionedNamespace(nameSpace string) string {
+	index := strings.LastIndex(nameSpace, ".")
+	if index == -1 {
+		return nameSpace
+	}
+	major, _, _ := splitVersion(nameSpace[index+1:])
+	if !strings.HasPrefix(nameSpace[index+1:], "v") || major == 0 {
+		return nameSpace
+	}
+	return nameSpace[:index]
 }
 
 type MemberType struct {
-	Name  string
-	Value string
+	Name            string
+	Value           string
+	Description     string
+	LongDescription string
+	Deprecated      string
 }
@@ -0,0 +1,91 @@
+package csdl
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// uriNodes generates the URITemplates from Redfish.Uris along with functions to build the first
+// URI from its parameters and to match a URI against all of them
+func (t *Type) uriNodes() []ast.Node {
+	if len(t.Uris) == 0 || t.ComplexType || t.Request {
+		return []ast.Node{}
+	}
+	name := t.GoTypeName()
+	src := &strings.Builder{}
+	src.WriteString(`
+// ` + name + `_URIs are the URIs of ` + name + ` resources, from Redfish.Uris
+var ` + name + `_URIs = []URITemplate{
+`)
+	for _, uri := range t.Uris {
+		src.WriteString(strconv.Quote(uri) + ",\n")
+	}
+	params := uriParamNames(t.Uris[0])
+	src.WriteString(`}
+
+// ` + name + `_URI returns the URI of a ` + name + `, ` + t.Uris[0] + `
+func ` + name + `_URI(` + strings.Join(params, ", "))
+	if len(params) != 0 {
+		src.WriteString(" string")
+	}
+	src.WriteString(`) string {
+	return ` + name + `_URIs[0].Expand(` + strings.Join(params, ", ") + `)
+}
+
+// ` + name + `_MatchURI returns the parameters of uri if it is one of the ` + name + `_URIs
+func ` + name + `_MatchURI(uri string) (map[string]string, bool) {
+	return matchURIs(` + name + `_URIs, uri)
+}
+`)
+	return []ast.Node{&sourceNode{src: src.String()}}
+}
+
+// uriParamNames returns Go parameter names for the {Name} segments of a URI, i.e. {ChassisId} is
+// chassisID
+func uriParamNames(uri string) []string {
+	names := []string{}
+	seen := map[string]bool{}
+	for {
+		start := strings.Index(uri, "{")
+		end := strings.Index(uri, "}")
+		if start == -1 || end < start {
+			return names
+		}
+		name := goParamName(uri[start+1 : end])
+		if !token.IsIdentifier(name) {
+			name = "param"
+		}
+		if token.IsKeyword(name) || seen[name] {
+			name += strconv.Itoa(len(names) + 1)
+		}
+		seen[name] = true
+		names = append(names, name)
+		uri = uri[end+1:]
+	}
+}
+
+// goParamName lower cases the leading initialism or word and spells a trailing Id as ID, so
+// PCIeDeviceId is pcIeDeviceID and ProcessorId2 is processorID2
+func goParamName(name string) string {
+	runes := []rune(name)
+	upper := 0
+	for upper < len(runes) && unicode.IsUpper(runes[upper]) {
+		upper++
+	}
+	if upper > 1 && upper < len(runes) && unicode.IsLower(runes[upper]) {
+		// The last capital starts the next word
+		upper--
+	}
+	for i := 0; i < upper; i++ {
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	name = string(runes)
+	digits := strings.TrimRightFunc(name, unicode.IsDigit)
+	if strings.HasSuffix(digits, "Id") {
+		name = strings.TrimSuffix(digits, "Id") + "ID" + name[len(digits):]
+	}
+	return name
+}
@@ -0,0 +1,214 @@
+package csdl
+
+import (
+	"fmt"
+	"go/ast"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// propField is a generated struct field along with the property it came from
+type propField struct {
+	Name  string
+	Prop  PropType
+	Field *ast.Field
+}
+
+// jsonName returns the name of the property in the JSON payload
+func (f *propField) jsonName() string {
+	if f.Prop.JsonName != "" {
+		return f.Prop.JsonName
+	}
+	return f.Name
+}
+
+// goType returns the generated type of the field split into the "*" or "[]" prefix and the base type
+func (f *propField) goType() (string, string) {
+	ident, ok := f.Field.Type.(*ast.Ident)
+	if !ok {
+		return "", ""
+	}
+	for _, prefix := range []string{"*", "[]"} {
+		if strings.HasPrefix(ident.Name, prefix) {
+			return prefix, strings.TrimPrefix(ident.Name, prefix)
+		}
+	}
+	return "", ident.Name
+}
+
+func isNumeric(goType string) bool {
+	switch goType {
+	case "byte", "int8", "int16", "int32", "int64", "float32", "float64":
+		return true
+	}
+	return false
+}
+
+// findGeneratedStruct returns the type that File will generate as the struct goType, i.e. the
+// version with the most properties
+func findGeneratedStruct(goType string, types map[string]*Type) (*Type, bool) {
+	found := ""
+	for name, t := range types {
+		if !t.isStruct() || t.GoTypeName() != goType {
+			continue
+		}
+		if found == "" {
+			found = name
+			continue
+		}
+		size, foundSize := len(t.Properties), len(types[found].Properties)
+		if size > foundSize || (size == foundSize && sortNamespace(name, found) > 0) {
+			found = name
+		}
+	}
+	if found == "" {
+		return nil, false
+	}
+	return types[found], true
+}
+
+// check generates a Validate check of a field, it is given the JSON path, the value and the field
+// expression
+type check func(path, value, field string) string
+
+// requiredCheck returns true if the field is required and Validate can tell when it is missing,
+// which it can't for a value that isn't a pointer, slice or map since the zero value could have
+// been in the payload. Check reports the required fields it can't.
+func (f *propField) requiredCheck() bool {
+	prefix, base := f.goType()
+	nillable := prefix != "" || base == "any" || strings.HasPrefix(base, "map[")
+	return f.Prop.Required && nillable
+}
+
+// valueChecks returns the checks of the field's value from the Validation annotations
+func (f *propField) valueChecks() []check {
+	_, base := f.goType()
+	checks := []check{}
+	if f.Prop.Pattern != "" && base == "string" {
+		_, err := regexp.Compile(f.Prop.Pattern)
+		// Check reports the patterns Go can't compile
+		if err == nil {
+			pattern := strconv.Quote(f.Prop.Pattern)
+			checks = append(checks, func(path, value, _ string) string {
+				return "errs.checkPattern(" + path + ", " + value + ", " + pattern + ")\n"
+			})
+		}
+	}
+	if f.Prop.Minimum != nil && isNumeric(base) {
+		minimum := formatNumber(*f.Prop.Minimum)
+		checks = append(checks, func(path, value, _ string) string {
+			return "errs.checkMinimum(" + path + ", float64(" + value + "), " + minimum + ")\n"
+		})
+	}
+	if f.Prop.Maximum != nil && isNumeric(base) {
+		maximum := formatNumber(*f.Prop.Maximum)
+		checks = append(checks, func(path, value, _ string) string {
+			return "errs.checkMaximum(" + path + ", float64(" + value + "), " + maximum + ")\n"
+		})
+	}
+	return checks
+}
+
+// nestedValidation returns true if the field is a complex type with a Validate method
+func (f *propField) nestedValidation(types map[string]*Type) bool {
+	_, base := f.goType()
+	nested, ok := findGeneratedStruct(base, types)
+	return ok && !f.Prop.Navigation && nested.hasValidation(types)
+}
+
+// validatedFields returns the fields Validate looks at, which are all of them except Oem and Actions
+func validatedFields(props []propField) []propField {
+	return slices.DeleteFunc(slices.Clone(props), func(prop propField) bool {
+		return prop.Name == "Oem" || prop.Name == "Actions"
+	})
+}
+
+// hasValidation returns true if Validate has something to check in the type, either directly or in
+// a nested complex type. Only these types get a Validate method.
+func (t *Type) hasValidation(types map[string]*Type) bool {
+	return t.validates(types, map[*Type]bool{})
+}
+
+// validates is hasValidation, seen stops recursive types from looping forever
+func (t *Type) validates(types map[string]*Type, seen map[*Type]bool) bool {
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+	props := []propField{}
+	for name, prop := range t.Properties {
+		props = append(props, propField{Name: name, Prop: prop, Field: prop.field(name, types, t.Replacements)})
+	}
+	for _, prop := range validatedFields(props) {
+		if prop.requiredCheck() || len(prop.valueChecks()) != 0 {
+			return true
+		}
+		_, base := prop.goType()
+		nested, ok := findGeneratedStruct(base, types)
+		if ok && !prop.Prop.Navigation && nested.validates(types, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateNodes generates the Validate method for a struct, which checks the Validation annotations
+func (t *Type) validateNodes(types map[string]*Type, props []propField) []ast.Node {
+	if !t.hasValidation(types) {
+		return []ast.Node{}
+	}
+	body := &strings.Builder{}
+	for _, prop := range validatedFields(props) {
+		if prop.requiredCheck() {
+			body.WriteString("errs.checkRequired(path+" + strconv.Quote("/"+prop.jsonName()) + ", t." + prop.Name + " != nil)\n")
+		}
+		checks := prop.valueChecks()
+		if prop.nestedValidation(types) {
+			checks = append(checks, func(path, _, field string) string {
+				return field + ".validate(" + path + ", errs)\n"
+			})
+		}
+		if len(checks) == 0 {
+			continue
+		}
+		prefix, _ := prop.goType()
+		field := "t." + prop.Name
+		path := "path+" + strconv.Quote("/"+prop.jsonName())
+		value := field
+		switch prefix {
+		case "*":
+			body.WriteString("if " + field + " != nil {\n")
+			value = "*" + field
+		case "[]":
+			body.WriteString("for i := range " + field + " {\n")
+			path = "jsonIndex(" + path + ", i)"
+			field += "[i]"
+			value = field
+		}
+		for _, check := range checks {
+			body.WriteString(check(path, value, field))
+		}
+		if prefix != "" {
+			body.WriteString("}\n")
+		}
+	}
+	return []ast.Node{&sourceNode{src: fmt.Sprintf(`
+// Validate checks the values against the Validation annotations in the schema and that the
+// required properties are present, including the values of nested complex types. Required fields
+// that can't be nil aren't checked. The error is a ValidationErrors listing every violation.
+func (t *%[1]s) Validate() error {
+	errs := ValidationErrors{}
+	t.validate("", &errs)
+	return errs.Err()
+}
+
+func (t *%[1]s) validate(path string, errs *ValidationErrors) {
+%[2]s}
+`, t.GoTypeName(), body.String())}}
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
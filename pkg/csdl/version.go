@@ -0,0 +1,69 @@
+package csdl
+
+import (
+	"cmp"
+	"go/ast"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// fieldVersionsNodes generates the version of the schema each property was added in and registers
+// them for ValidFields. The fields are keyed by their names in the payload. Properties from other
+// schemas, like the ones Resource defines, are in every version and have no version.
+func (t *Type) fieldVersionsNodes(types map[string]*Type) []ast.Node {
+	unversioned := unversionedNamespace(t.Namespace)
+	if !t.FieldVersions || t.Request || unversioned == t.Namespace {
+		return []ast.Node{}
+	}
+	name := t.GoTypeName()
+	src := &strings.Builder{}
+	src.WriteString("\n")
+	for _, comment := range docComment(name + "_FieldVersions are the versions of the schema each field of " + name + " was added in, the fields without one are in every version").List {
+		src.WriteString(comment.Text + "\n")
+	}
+	src.WriteString("var " + name + "_FieldVersions = map[string]string{\n")
+	for _, propName := range slices.Sorted(maps.Keys(t.Properties)) {
+		jsonName := cmp.Or(t.Properties[propName].JsonName, propName)
+		src.WriteString(strconv.Quote(jsonName) + `: ` + strconv.Quote(t.propertyVersion(propName, types)) + `,
+`)
+	}
+	src.WriteString(`}
+
+func init() {
+	fieldVersions[` + strconv.Quote(unversioned+"."+t.Name) + `] = ` + name + `_FieldVersions
+}
+`)
+	return []ast.Node{&sourceNode{src: src.String()}}
+}
+
+// propertyVersion returns the version of the schema a property was added in, which is the oldest
+// version of the type that declares it. Newer versions can declare it again, to change its
+// annotations, so the namespace of the property isn't always the one it was added in.
+func (t *Type) propertyVersion(propName string, types map[string]*Type) string {
+	unversioned := unversionedNamespace(t.Namespace)
+	version := ""
+	for _, other := range types {
+		prop, ok := other.Properties[propName]
+		if !ok || other.Name != t.Name || unversionedNamespace(other.Namespace) != unversioned {
+			continue
+		}
+		if prop.Namespace == unversioned || unversionedNamespace(prop.Namespace) != unversioned {
+			// From another schema, so in every version
+			return ""
+		}
+		since := prop.Namespace[len(unversioned)+1:]
+		if version == "" || compareVersions(since, version) < 0 {
+			version = since
+		}
+	}
+	return version
+}
+
+// compareVersions orders schema versions like v1_10_0 by number
+func compareVersions(a string, b string) int {
+	aMajor, aMinor, aRev := splitVersion(a)
+	bMajor, bMinor, bRev := splitVersion(b)
+	return cmp.Or(cmp.Compare(aMajor, bMajor), cmp.Compare(aMinor, bMinor), cmp.Compare(aRev, bRev))
+}
@@ -0,0 +1,34 @@
+package csdl
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFieldVersions(t *testing.T) {
+	v1 := &Type{Name: "Thing", Namespace: "Thing.v1_0_0", FieldVersions: true, Properties: map[string]PropType{
+		"ID":       {Type: "Edm.String", JsonName: "@odata.id"},
+		"Name":     {Type: "Edm.String", Namespace: "Resource.v1_0_0"},
+		"HostName": {Type: "Edm.String", Namespace: "Thing.v1_0_0"},
+	}}
+	v2 := &Type{Name: "Thing", Namespace: "Thing.v1_10_0", FieldVersions: true, Properties: map[string]PropType{
+		"ID":       {Type: "Edm.String", JsonName: "@odata.id"},
+		"Name":     {Type: "Edm.String", Namespace: "Resource.v1_0_0"},
+		"HostName": {Type: "Edm.String", Namespace: "Thing.v1_10_0"},
+		"Location": {Type: "Edm.String", Namespace: "Thing.v1_2_0"},
+	}}
+	types := map[string]*Type{"Thing.v1_0_0.Thing": v1, "Thing.v1_10_0.Thing": v2}
+	nodes := v2.fieldVersionsNodes(types)
+	if len(nodes) != 1 {
+		t.Fatalf("got %d nodes, want 1", len(nodes))
+	}
+	src := nodes[0].(*sourceNode).src
+	for _, want := range []string{`"@odata.id": "",`, `"Name": "",`, `"HostName": "v1_0_0",`, `"Location": "v1_2_0",`} {
+		if !strings.Contains(src, want) {
+			t.Errorf("the versions don't have %s:\n%s", want, src)
+		}
+	}
+	if strings.Contains(src, `"ID"`) {
+		t.Errorf("the versions are keyed by the Go name of @odata.id:\n%s", src)
+	}
+}
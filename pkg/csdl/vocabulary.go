@@ -0,0 +1,114 @@
+package csdl
+
+import "strings"
+
+// vocabulary is what the loaded documents define about terms, enough to tell which annotation values
+// are enum members. Terms and types are named with the aliases in knownVocabularies, like the terms
+// of annotations are.
+type vocabulary struct {
+	// terms are the types of the terms, without Collection()
+	terms map[string]string
+	// properties are the types of the properties of the complex types, without Collection()
+	properties map[string]map[string]string
+	baseTypes  map[string]string
+	enums      map[string]bool
+}
+
+func newVocabulary(documents map[string]*Edmx) *vocabulary {
+	v := &vocabulary{
+		terms:      map[string]string{},
+		properties: map[string]map[string]string{},
+		baseTypes:  map[string]string{},
+		enums:      map[string]bool{},
+	}
+	for _, edmx := range documents {
+		v.add(edmx)
+	}
+	return v
+}
+
+// add adds the terms and types of a document, the names aren't resolved yet so it uses its own
+// symbol table. Unresolved names are reported when the document itself is resolved.
+func (v *vocabulary) add(edmx *Edmx) {
+	symbols := newSymbolTable("", edmx, nil)
+	typeName := func(name string) string {
+		elementType, ok := strings.CutPrefix(name, "Collection(")
+		if ok {
+			name = strings.TrimSuffix(elementType, ")")
+		}
+		return knownName(symbols.resolveType(name, "", 0))
+	}
+	for _, schema := range edmx.DataServices.Schema {
+		for _, term := range schema.Term {
+			v.terms[knownName(schema.Namespace+"."+term.Name)] = typeName(term.Type)
+		}
+		for _, complexType := range schema.ComplexType {
+			name := knownName(schema.Namespace + "." + complexType.Name)
+			properties := map[string]string{}
+			for _, property := range complexType.Property {
+				properties[property.Name] = typeName(property.Type)
+			}
+			v.properties[name] = properties
+			if complexType.BaseType != "" {
+				v.baseTypes[name] = typeName(complexType.BaseType)
+			}
+		}
+		for _, enumType := range schema.EnumType {
+			v.enums[knownName(schema.Namespace+"."+enumType.Name)] = true
+		}
+	}
+}
+
+// propertyType returns the type of a property of a complex type or one of its base types, or "" if
+// it doesn't have it
+func (v *vocabulary) propertyType(typeName string, property string) string {
+	seen := map[string]bool{}
+	for typeName != "" && !seen[typeName] {
+		seen[typeName] = true
+		propertyType, ok := v.properties[typeName][property]
+		if ok {
+			return propertyType
+		}
+		typeName = v.baseTypes[typeName]
+	}
+	return ""
+}
+
+// valueType is the declared type of an annotation value. It is looked up in the vocabulary if it
+// defines the term, otherwise the vocabulary is nil and name is the path of the value in enumTerms.
+type valueType struct {
+	vocabulary *vocabulary
+	name       string
+}
+
+// termType returns the type of the values of a term
+func (v *vocabulary) termType(term string) valueType {
+	typeName, ok := v.terms[term]
+	if !ok {
+		return valueType{name: term}
+	}
+	return valueType{vocabulary: v, name: typeName}
+}
+
+// enumType returns the enum type if the value is an enum member
+func (t valueType) enumType() (string, bool) {
+	if t.vocabulary == nil {
+		enumType, ok := enumTerms[t.name]
+		return enumType, ok
+	}
+	return t.name, t.vocabulary.enums[t.name]
+}
+
+// property returns the type of a property of a record value, recordType is the type the record
+// names if it is a derived type
+func (t valueType) property(property string, recordType string) valueType {
+	if t.vocabulary == nil {
+		return valueType{name: t.name + "/" + property}
+	}
+	typeName := t.name
+	_, ok := t.vocabulary.properties[recordType]
+	if ok {
+		typeName = recordType
+	}
+	return valueType{vocabulary: t.vocabulary, name: t.vocabulary.propertyType(typeName, property)}
+}
@@ -0,0 +1,197 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change
+const diffContext = 3
+
+// Diff compares the generated files to the ones in dir and returns a unified diff from the files on
+// disk to the generated ones, empty if everything is up to date. Files that are in dir but weren't
+// generated are ignored.
+func Diff(dir string, files map[string][]byte) (string, error) {
+	buf := strings.Builder{}
+	for _, fileName := range slices.Sorted(maps.Keys(files)) {
+		path := filepath.Join(dir, fileName)
+		onDisk, err := os.ReadFile(path)
+		oldName := path
+		if errors.Is(err, fs.ErrNotExist) {
+			oldName = "/dev/null"
+		} else if err != nil {
+			return "", err
+		}
+		buf.WriteString(unifiedDiff(oldName, path, string(onDisk), string(files[fileName])))
+	}
+	return buf.String(), nil
+}
+
+type diffOp struct {
+	kind byte // ' ' for unchanged lines, '-' for removed, '+' for added
+	line string
+}
+
+// splitLines splits text into lines, keeping the line endings so a missing newline at the end shows up
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// unifiedDiff returns the unified diff from oldText to newText, empty if they're the same
+func unifiedDiff(oldName string, newName string, oldText string, newText string) string {
+	if oldText == newText {
+		return ""
+	}
+	ops := diffLines(splitLines(oldText), splitLines(newText))
+	buf := strings.Builder{}
+	buf.WriteString("--- " + oldName + "\n+++ " + newName + "\n")
+	// oldLine and newLine are the number of lines of each file before ops[i]
+	oldLine, newLine := 0, 0
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			oldLine++
+			newLine++
+			i++
+			continue
+		}
+		// Back up for the leading context, then extend the hunk until there's a long enough run of
+		// unchanged lines to end it
+		start := max(i-diffContext, 0)
+		hunkOld := oldLine - (i - start)
+		hunkNew := newLine - (i - start)
+		end := i
+		unchanged := 0
+		for end < len(ops) && unchanged <= 2*diffContext {
+			if ops[end].kind == ' ' {
+				unchanged++
+			} else {
+				unchanged = 0
+			}
+			end++
+		}
+		end -= max(unchanged-diffContext, 0)
+		oldCount, newCount := 0, 0
+		for _, op := range ops[start:end] {
+			if op.kind != '+' {
+				oldCount++
+			}
+			if op.kind != '-' {
+				newCount++
+			}
+		}
+		buf.WriteString(fmt.Sprintf("@@ -%s +%s @@\n", hunkRange(hunkOld, oldCount), hunkRange(hunkNew, newCount)))
+		for _, op := range ops[start:end] {
+			buf.WriteByte(op.kind)
+			buf.WriteString(op.line)
+			if !strings.HasSuffix(op.line, "\n") {
+				buf.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		oldLine = hunkOld + oldCount
+		newLine = hunkNew + newCount
+		i = end
+	}
+	return buf.String()
+}
+
+// hunkRange formats the start and length of a hunk, start is the number of lines before the hunk
+func hunkRange(start int, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// diffLines returns the shortest edit script from a to b using Myers' algorithm
+func diffLines(a []string, b []string) []diffOp {
+	// The common prefix and suffix are trimmed first since generated files mostly don't change
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	ops := make([]diffOp, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	ops = append(ops, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	return ops
+}
+
+func myers(a []string, b []string) []diffOp {
+	n, m := len(a), len(b)
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+	// trace[d] is the part of v that step d can reach, saved before step d
+	trace := [][]int{}
+	found := false
+	for d := 0; d <= n+m && !found; d++ {
+		trace = append(trace, slices.Clone(v[offset-d-1:offset+d+2]))
+		for k := -d; k <= d; k += 2 {
+			x := v[offset+k-1] + 1
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				found = true
+				break
+			}
+		}
+	}
+	// Walk back through the trace to recover the edits, in reverse
+	reversed := []diffOp{}
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		saved := trace[d]
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && saved[k-1+d+1] < saved[k+1+d+1]) {
+			prevK = k + 1
+		}
+		prevX := saved[prevK+d+1]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			reversed = append(reversed, diffOp{' ', a[x-1]})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				reversed = append(reversed, diffOp{'+', b[y-1]})
+			} else {
+				reversed = append(reversed, diffOp{'-', a[x-1]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+	slices.Reverse(reversed)
+	return reversed
+}
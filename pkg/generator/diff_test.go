@@ -0,0 +1,75 @@
+package generator
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	dir := t.TempDir()
+	onDisk := map[string]string{
+		"Same.go":    "package standard\n",
+		"Changed.go": "package standard\n\ntype A int\ntype B int\ntype C int\n",
+		"Extra.go":   "package standard\n",
+	}
+	for name, content := range onDisk {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	diff, err := Diff(dir, map[string][]byte{"Same.go": []byte(onDisk["Same.go"])})
+	if err != nil || diff != "" {
+		t.Errorf("got %q, %v for files that are up to date", diff, err)
+	}
+	diff, err = Diff(dir, map[string][]byte{
+		"Same.go":    []byte(onDisk["Same.go"]),
+		"Changed.go": []byte("package standard\n\ntype A int\ntype B string\ntype C int\n"),
+		"New.go":     []byte("package standard"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	changed, added := filepath.Join(dir, "Changed.go"), filepath.Join(dir, "New.go")
+	want := "--- " + changed + "\n+++ " + changed + "\n" +
+		"@@ -1,5 +1,5 @@\n" +
+		" package standard\n \n type A int\n-type B int\n+type B string\n type C int\n" +
+		"--- /dev/null\n+++ " + added + "\n" +
+		"@@ -0,0 +1 @@\n" +
+		"+package standard\n\\ No newline at end of file\n"
+	if diff != want {
+		t.Errorf("got diff\n%s\nwant\n%s", diff, want)
+	}
+}
+
+// TestDiffLines checks that the edit script turns a into b, using only the lines of a and b, for
+// random edits of a file
+func TestDiffLines(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	words := []string{"a\n", "b\n", "c\n", "d\n"}
+	randomLines := func() []string {
+		lines := make([]string, random.Intn(20))
+		for i := range lines {
+			lines[i] = words[random.Intn(len(words))]
+		}
+		return lines
+	}
+	for range 1000 {
+		a, b := randomLines(), randomLines()
+		oldLines, newLines := []string{}, []string{}
+		for _, op := range diffLines(a, b) {
+			if op.kind != '+' {
+				oldLines = append(oldLines, op.line)
+			}
+			if op.kind != '-' {
+				newLines = append(newLines, op.line)
+			}
+		}
+		if strings.Join(oldLines, "") != strings.Join(a, "") || strings.Join(newLines, "") != strings.Join(b, "") {
+			t.Fatalf("the edit script from %q to %q doesn't reproduce them", a, b)
+		}
+	}
+}
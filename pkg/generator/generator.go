@@ -0,0 +1,141 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/pboyd04/gocsdl/pkg/csdl"
+	"github.com/pboyd04/gocsdl/pkg/odata"
+)
+
+// ErrFatalDiagnostics is returned by Generate when the diagnostics include errors, or warnings when
+// WarningsAreErrors is set
+var ErrFatalDiagnostics = errors.New("the CSDL has problems that stop generation")
+
+// Generator turns parsed CSDL into Go source files
+type Generator struct {
+	PackageName string
+	// IndividualFiles puts each namespace prefix in its own file along with odata.go, otherwise
+	// everything is generated into <PackageName>.go
+	IndividualFiles   bool
+	WarningsAreErrors bool
+	// Client generates navigation properties as Link[T] along with the client to follow them
+	Client bool
+	// Server generates interfaces for each resource and a router that calls them
+	Server bool
+	// StrictEnums generates enums that fail to unmarshal values the schema doesn't define
+	StrictEnums bool
+	// FieldVersions generates the version each field was added in along with ValidFields to list
+	// the fields of an @odata.type
+	FieldVersions bool
+}
+
+func New(packageName string) *Generator {
+	return &Generator{
+		PackageName:     packageName,
+		IndividualFiles: true,
+	}
+}
+
+// Generate parses and folds the parser's files and returns the generated source by file name,
+// along with the problems found in the CSDL
+func (g *Generator) Generate(parser *csdl.Parser) (map[string][]byte, csdl.Diagnostics, error) {
+	parser.TypedLinks = g.Client
+	parser.Handlers = g.Server
+	parser.StrictEnums = g.StrictEnums
+	parser.FieldVersions = g.FieldVersions
+	types, err := parser.Parse()
+	if err != nil {
+		return nil, nil, err
+	}
+	parser.Fold(types)
+	diagnostics := append(parser.Diagnostics, csdl.Check(types)...)
+	diagnostics.Sort()
+	if diagnostics.Fatal(g.WarningsAreErrors) {
+		return nil, diagnostics, ErrFatalDiagnostics
+	}
+	if !g.IndividualFiles {
+		files, err := g.singleFile(types)
+		return files, diagnostics, err
+	}
+	files, err := g.individualFiles(types)
+	return files, diagnostics, err
+}
+
+func (g *Generator) individualFiles(types map[string]*csdl.Type) (map[string][]byte, error) {
+	boilerPlate, err := odata.BoilerPlate(g.PackageName, odata.Options{Client: g.Client, Server: g.Server, FieldVersions: g.FieldVersions})
+	if err != nil {
+		return nil, fmt.Errorf("error generating boilerplate: %w", err)
+	}
+	ret := map[string][]byte{odata.Filename: boilerPlate}
+	files := map[string]*csdl.File{}
+	// Add the types oldest version first so that newer versions win when they are the same size
+	for _, name := range csdl.SortedNames(types) {
+		prefix := splitNamespacePrefix(name)
+		file, ok := files[prefix]
+		if !ok {
+			file = csdl.NewFile(g.PackageName)
+			files[prefix] = file
+		}
+		err = file.AddType(types[name])
+		if err != nil {
+			return nil, fmt.Errorf("error adding type %s to file %s: %w", name, prefix, err)
+		}
+	}
+	for _, prefix := range slices.Sorted(maps.Keys(files)) {
+		fileName := prefix + ".go"
+		ret[fileName], err = files[prefix].Flush(types)
+		if err != nil {
+			return nil, fmt.Errorf("error generating file %s: %w", fileName, err)
+		}
+	}
+	return ret, nil
+}
+
+func (g *Generator) singleFile(types map[string]*csdl.Type) (map[string][]byte, error) {
+	boilerPlate, err := odata.BoilerPlate(g.PackageName, odata.Options{Client: g.Client, Server: g.Server, FieldVersions: g.FieldVersions})
+	if err != nil {
+		return nil, fmt.Errorf("error generating boilerplate: %w", err)
+	}
+	file := csdl.NewFileFromSource(boilerPlate)
+	for _, name := range csdl.SortedNames(types) {
+		err = file.AddType(types[name])
+		if err != nil {
+			return nil, fmt.Errorf("error adding type %s: %w", name, err)
+		}
+	}
+	fileName := g.PackageName + ".go"
+	data, err := file.Flush(types)
+	if err != nil {
+		return nil, fmt.Errorf("error generating file %s: %w", fileName, err)
+	}
+	return map[string][]byte{fileName: data}, nil
+}
+
+func splitNamespacePrefix(name string) string {
+	index := strings.Index(name, ".")
+	if index == -1 {
+		return name
+	}
+	return name[:index]
+}
+
+// WriteFiles writes the generated files to dir, creating it if needed
+func WriteFiles(dir string, files map[string][]byte) error {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return err
+	}
+	for _, fileName := range slices.Sorted(maps.Keys(files)) {
+		err = os.WriteFile(filepath.Join(dir, fileName), files[fileName], 0o644)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
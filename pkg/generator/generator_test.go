@@ -0,0 +1,654 @@
+package generator
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pboyd04/gocsdl/pkg/csdl"
+)
+
+// fixtureParser returns a parser with the CSDL documents in testdata/<fixture> added
+func fixtureParser(t *testing.T, fixture string) *csdl.Parser {
+	t.Helper()
+	parser := csdl.NewParser()
+	t.Cleanup(func() { _ = parser.Close() })
+	fixtures, err := filepath.Glob(filepath.Join("testdata", fixture, "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, fileName := range fixtures {
+		file, err := os.Open(fileName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parser.AddFile(filepath.Base(fileName), file)
+	}
+	return parser
+}
+
+// goTest generates the CSDL in testdata/<fixture> into a module along with the test files and runs
+// go test in it
+func goTest(t *testing.T, gen *Generator, fixture string, files map[string]string) {
+	t.Helper()
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go isn't installed")
+	}
+	generated, diagnostics, err := gen.Generate(fixtureParser(t, fixture))
+	if err != nil {
+		t.Fatalf("Generate: %v\n%v", err, diagnostics)
+	}
+	dir := t.TempDir()
+	err = WriteFiles(dir, generated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files["go.mod"] = "module example.com/" + gen.PackageName + "\n\ngo 1.23\n"
+	for name, content := range files {
+		err = os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	cmd := exec.Command(goBin, "test", "./...")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("go test of the generated code failed: %v\n%s", err, out)
+	}
+}
+
+// clientTest serves ServiceRoot -> Systems -> ComputerSystem -> Links.Chassis from a stand-in BMC
+// and follows the links with the typed client
+const clientTest = `package standard
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var resources = map[string]string{
+	"/redfish/v1": ` + "`" + `{"@odata.id": "/redfish/v1", "Id": "RootService", "Name": "Root", "RedfishVersion": "1.0.0",
+		"Systems": {"@odata.id": "/redfish/v1/Systems"}}` + "`" + `,
+	"/redfish/v1/Systems": ` + "`" + `{"@odata.id": "/redfish/v1/Systems", "Name": "Systems",
+		"Members": [{"@odata.id": "/redfish/v1/Systems/1"}], "Members@odata.count": 2,
+		"Members@odata.nextLink": "/redfish/v1/Systems?$skip=1"}` + "`" + `,
+	"/redfish/v1/Systems?$skip=1": ` + "`" + `{"@odata.id": "/redfish/v1/Systems", "Name": "Systems",
+		"Members": [{"@odata.id": "/redfish/v1/Systems/2"}], "Members@odata.count": 2}` + "`" + `,
+	"/redfish/v1/Systems/1": ` + "`" + `{"@odata.id": "/redfish/v1/Systems/1", "Id": "1", "Name": "System", "PowerState": "On",
+		"Links": {"Chassis": [{"@odata.id": "/redfish/v1/Chassis/1"}]}}` + "`" + `,
+	"/redfish/v1/Chassis/1": ` + "`" + `{"@odata.id": "/redfish/v1/Chassis/1", "Id": "1", "Name": "Chassis", "ChassisType": "Rack"}` + "`" + `,
+	"/redfish/v1/Bad": ` + "`" + `{"Id": ` + "`" + `,
+}
+
+func newBMC(t *testing.T) *HTTPClient {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Auth-Token") != "token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, ok := resources[r.URL.RequestURI()]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(` + "`" + `{"error": {"code": "Base.1.0.ResourceMissingAtURI"}}` + "`" + `))
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return NewHTTPClient(server.URL, server.Client(), SessionAuth{Token: "token"})
+}
+
+func TestFollowLinks(t *testing.T) {
+	ctx := context.Background()
+	client := newBMC(t)
+	root, err := Fetch[ServiceRoot](ctx, client, "/redfish/v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	systems, err := root.Systems.Get(ctx, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(systems.Members) != 1 {
+		t.Fatalf("got %d members, want 1", len(systems.Members))
+	}
+	system, err := systems.Members[0].Get(ctx, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if system.ID != "/redfish/v1/Systems/1" || system.PowerState != Resource_PowerState_On {
+		t.Errorf("got system %s with PowerState %s", system.ID, system.PowerState)
+	}
+	if len(system.Links.Chassis) != 1 {
+		t.Fatalf("got %d chassis links, want 1", len(system.Links.Chassis))
+	}
+	chassis, err := system.Links.Chassis[0].Get(ctx, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chassis.ChassisType != Chassis_ChassisType_Rack {
+		t.Errorf("got ChassisType %s, want Rack", chassis.ChassisType)
+	}
+}
+
+func TestCollectionAll(t *testing.T) {
+	ctx := context.Background()
+	client := newBMC(t)
+	fetch := func(nextLink string) (*Collection[Link[ComputerSystem]], error) {
+		return Fetch[Collection[Link[ComputerSystem]]](ctx, client, nextLink)
+	}
+	systems, err := fetch("/redfish/v1/Systems")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !systems.HasNextPage() {
+		t.Fatal("the first page doesn't have a next link")
+	}
+	members, err := systems.All(fetch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(members) != 2 || members[0].ID != "/redfish/v1/Systems/1" || members[1].ID != "/redfish/v1/Systems/2" {
+		t.Errorf("got members %v", members)
+	}
+}
+
+func TestCollectionAllNilPage(t *testing.T) {
+	systems := &Collection[Link[ComputerSystem]]{
+		Members:         []Link[ComputerSystem]{{ID: "/redfish/v1/Systems/1"}},
+		MembersNextLink: "/redfish/v1/Systems?$skip=1",
+	}
+	members, err := systems.All(func(string) (*Collection[Link[ComputerSystem]], error) {
+		return nil, nil
+	})
+	if err != nil || len(members) != 1 {
+		t.Errorf("got members %v, %v, want the first page", members, err)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	ctx := context.Background()
+	client := newBMC(t)
+	_, err := Fetch[ComputerSystem](ctx, client, "/redfish/v1/Systems/3")
+	httpErr := &HTTPError{}
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusNotFound {
+		t.Errorf("got %v for a missing resource, want a 404 HTTPError", err)
+	}
+	unauthenticated := NewHTTPClient(client.BaseURL, client.Transport, nil)
+	_, err = Fetch[ServiceRoot](ctx, unauthenticated, "/redfish/v1")
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("got %v without credentials, want a 401 HTTPError", err)
+	}
+	_, err = Fetch[ComputerSystem](ctx, client, "/redfish/v1/Bad")
+	if err == nil || errors.As(err, &httpErr) {
+		t.Errorf("got %v for bad JSON, want a JSON error", err)
+	}
+	_, err = Link[Chassis]{}.Get(ctx, client)
+	if err == nil {
+		t.Error("following an empty link didn't fail")
+	}
+}
+`
+
+func TestClient(t *testing.T) {
+	gen := New("standard")
+	gen.Client = true
+	goTest(t, gen, "redfish", map[string]string{"client_test.go": clientTest})
+}
+
+// validateTest checks the errors Validate reports and that only types with constraints have it
+const validateTest = `package standard
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tag, count, good, bad := "not valid!", int64(5000), "1.0", "one"
+	system := &ComputerSystem{
+		AssetTag:         &tag,
+		ProcessorSummary: ComputerSystem_ProcessorSummary{Count: &count},
+		TrustedModules:   []ComputerSystem_TrustedModules{{FirmwareVersion: &good}, {FirmwareVersion: &bad}},
+	}
+	errs := ValidationErrors{}
+	if !errors.As(system.Validate(), &errs) {
+		t.Fatal("Validate didn't return ValidationErrors")
+	}
+	paths := []string{}
+	for _, err := range errs {
+		paths = append(paths, err.Path)
+	}
+	want := []string{"/AssetTag", "/ProcessorSummary/Count", "/TrustedModules/1/FirmwareVersion"}
+	if !slices.Equal(paths, want) {
+		t.Errorf("got errors for %v, want %v", paths, want)
+	}
+	tag, count, bad = "Rack 1", 4, "2.1"
+	err := system.Validate()
+	if err != nil {
+		t.Errorf("a valid system failed: %v", err)
+	}
+	err = (&ComputerSystemCollection{}).Validate()
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Path != "/Members" {
+		t.Errorf("got %v for a collection without Members, want a required error", err)
+	}
+	_, ok := any(&ServiceRoot{}).(interface{ Validate() error })
+	if ok {
+		t.Error("ServiceRoot has a Validate method but nothing to check")
+	}
+}
+`
+
+func TestValidate(t *testing.T) {
+	goTest(t, New("standard"), "redfish", map[string]string{"validate_test.go": validateTest})
+}
+
+// patchTest checks that Patch structs only have the writable properties and only send the ones
+// that are set
+const patchTest = `package standard
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestPatch(t *testing.T) {
+	tag, target := "Rack 1", ComputerSystem_BootSource_Pxe
+	patch := ComputerSystemPatch{AssetTag: &tag, Boot: &ComputerSystem_BootPatch{BootSourceOverrideTarget: &target}}
+	b, err := json.Marshal(patch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ` + "`" + `{"AssetTag":"Rack 1","Boot":{"BootSourceOverrideTarget":"Pxe"}}` + "`" + `
+	if string(b) != want {
+		t.Errorf("got %s, want %s", b, want)
+	}
+	patchType := reflect.TypeOf(ComputerSystemPatch{})
+	for _, name := range []string{"SystemType", "PowerState", "UUID", "ProcessorSummary", "Status"} {
+		_, ok := patchType.FieldByName(name)
+		if ok {
+			t.Errorf("the read only property %s is in ComputerSystemPatch", name)
+		}
+	}
+	_, ok := reflect.TypeOf(ChassisPatch{}).FieldByName("Links")
+	if !ok {
+		t.Error("ChassisPatch doesn't have Links, which has the writable ContainedBy")
+	}
+}
+
+func TestPropertyPermission(t *testing.T) {
+	tests := map[string]Permission{
+		"AssetTag":     Permission_ReadWrite,
+		"SystemType":   Permission_Read,
+		"IndicatorLED": Permission_ReadWrite,
+	}
+	system := &ComputerSystem{}
+	for property, want := range tests {
+		got, ok := system.PropertyPermission(property)
+		if !ok || got != want {
+			t.Errorf("PropertyPermission(%q) = %s, %t, want %s", property, got, ok, want)
+		}
+	}
+	_, ok := system.PropertyPermission("Boot")
+	if ok {
+		t.Error("PropertyPermission found a permission for Boot, which doesn't have one")
+	}
+	if !Permission_ReadWrite.Writable() || !Permission_Write.Writable() || Permission_Read.Writable() {
+		t.Error("Writable doesn't match the permissions that allow PATCH")
+	}
+}
+`
+
+func TestPatch(t *testing.T) {
+	goTest(t, New("standard"), "redfish", map[string]string{"patch_test.go": patchTest})
+}
+
+// createTest checks the Create struct sends the required properties and Validate reports the
+// missing ones
+const createTest = `package standard
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestCreate(t *testing.T) {
+	b, err := json.Marshal(ChassisCreate{})
+	if err != nil || string(b) != ` + "`" + `{"ChassisType":null}` + "`" + ` {
+		t.Errorf("got %s, %v, want only the required ChassisType", b, err)
+	}
+	errs := ValidationErrors{}
+	err = (&ChassisCreate{}).Validate()
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Path != "/ChassisType" {
+		t.Errorf("got %v without ChassisType, want it to be required", err)
+	}
+	chassisType, tag := Chassis_ChassisType_Rack, "Rack 1"
+	err = (&ChassisCreate{ChassisType: &chassisType, AssetTag: &tag}).Validate()
+	if err != nil {
+		t.Errorf("got %v for a complete ChassisCreate", err)
+	}
+}
+`
+
+func TestCreate(t *testing.T) {
+	goTest(t, New("standard"), "redfish", map[string]string{"create_test.go": createTest})
+}
+
+func TestRequiredDiagnostics(t *testing.T) {
+	_, diagnostics, err := New("standard").Generate(fixtureParser(t, "redfish"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Name == "Chassis.v1_0_0.Chassis/ChassisType" && strings.Contains(diagnostic.Message, "Redfish.Required") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("ChassisType is required but can't be nil and there's no diagnostic about it, got %v", diagnostics)
+	}
+}
+
+// aliasTest checks the types named through an alias, ComputerSystem_v1.xml includes
+// Resource.v1_1_0 as Res
+const aliasTest = `package standard
+
+var _ Resource_Location = ComputerSystem{}.Location
+`
+
+func TestAliases(t *testing.T) {
+	goTest(t, New("standard"), "redfish", map[string]string{"alias_test.go": aliasTest})
+}
+
+// uriTest checks the functions generated from Redfish.Uris
+const uriTest = `package standard
+
+import "testing"
+
+func TestURIs(t *testing.T) {
+	if got := ComputerSystem_URI("1"); got != "/redfish/v1/Systems/1" {
+		t.Errorf("ComputerSystem_URI = %s", got)
+	}
+	if got := Chassis_URI("Rack 1"); got != "/redfish/v1/Chassis/Rack%201" {
+		t.Errorf("Chassis_URI = %s", got)
+	}
+	values, ok := ComputerSystem_MatchURI("/redfish/v1/CompositionService/ResourceBlocks/Block1/Systems/2")
+	if !ok || values["ResourceBlockId"] != "Block1" || values["ComputerSystemId"] != "2" {
+		t.Errorf("ComputerSystem_MatchURI = %v, %t", values, ok)
+	}
+	_, ok = ComputerSystem_MatchURI("/redfish/v1/Chassis/1")
+	if ok {
+		t.Error("ComputerSystem_MatchURI matched a Chassis")
+	}
+}
+`
+
+func TestURIs(t *testing.T) {
+	goTest(t, New("standard"), "redfish", map[string]string{"uri_test.go": uriTest})
+}
+
+// fieldVersionsTest checks ValidFields, ComputerSystem.v1_1_0 declares HostName again which was added
+// in v1_0_0
+const fieldVersionsTest = `package standard
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestValidFields(t *testing.T) {
+	fields, ok := ValidFields("#ComputerSystem.v1_0_0.ComputerSystem")
+	if !ok || !slices.Contains(fields, "HostName") || !slices.Contains(fields, "Id") || slices.Contains(fields, "Location") {
+		t.Errorf("ValidFields of v1_0_0 = %v, %t", fields, ok)
+	}
+	fields, ok = ValidFields("#ComputerSystem.v1_1_0.ComputerSystem")
+	if !ok || !slices.Contains(fields, "HostName") || !slices.Contains(fields, "Location") {
+		t.Errorf("ValidFields of v1_1_0 = %v, %t", fields, ok)
+	}
+}
+`
+
+func TestFieldVersions(t *testing.T) {
+	gen := New("standard")
+	gen.FieldVersions = true
+	goTest(t, gen, "redfish", map[string]string{"fieldversions_test.go": fieldVersionsTest})
+}
+
+// enumTest round-trips the integer backed and flags enums in testdata/enums through JSON, they are
+// written as the names of their members
+const enumTest = `package standard
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestEnumRoundTrip(t *testing.T) {
+	payload := ` + "`" + `{"Access":"Read,Execute","Big":"Huge","Tiny":"B"}` + "`" + `
+	thing := Enums_Thing{}
+	err := json.Unmarshal([]byte(payload), &thing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if Enums_Big_Huge != 10000000000 || Enums_Tiny_B != 1 {
+		t.Errorf("Huge = %d and B = %d, want the member values", Enums_Big_Huge, Enums_Tiny_B)
+	}
+	if thing.Big == nil || *thing.Big != Enums_Big_Huge {
+		t.Errorf("Big = %v", thing.Big)
+	}
+	if thing.Tiny == nil || *thing.Tiny != Enums_Tiny_B {
+		t.Errorf("Tiny = %v", thing.Tiny)
+	}
+	if thing.Access == nil || !thing.Access.Has(Enums_Access_Read|Enums_Access_Execute) || thing.Access.Has(Enums_Access_Write) {
+		t.Errorf("Access = %v", thing.Access)
+	}
+	out, err := json.Marshal(thing)
+	if err != nil || string(out) != payload {
+		t.Errorf("Marshal = %s, %v, want %s", out, err, payload)
+	}
+	access := Enums_Access(0)
+	out, err = json.Marshal(access)
+	if err != nil || string(out) != ` + "`" + `"None"` + "`" + ` {
+		t.Errorf("Marshal of no flags = %s, %v", out, err)
+	}
+	access.Set(Enums_Access_Write)
+	access.Set(Enums_Access_Read)
+	access.Clear(Enums_Access_Write)
+	if access != Enums_Access_Read || access.String() != "Read" {
+		t.Errorf("Set and Clear gave %s", access)
+	}
+}
+
+func TestEnumInvalid(t *testing.T) {
+	_, err := json.Marshal(Enums_Big(5))
+	if err == nil {
+		t.Error("marshaled a Big that isn't a member")
+	}
+	_, err = json.Marshal(Enums_Access(8))
+	if err == nil {
+		t.Error("marshaled an Access with a bit that isn't a flag")
+	}
+	if got := Enums_Access(9).String(); got != "Read,8" {
+		t.Errorf("String = %s, want Read,8", got)
+	}
+	for _, payload := range []string{` + "`" + `{"Tiny":"C"}` + "`" + `, ` + "`" + `{"Access":"Read,Delete"}` + "`" + `, ` + "`" + `{"Big":1}` + "`" + `} {
+		err = json.Unmarshal([]byte(payload), &Enums_Thing{})
+		if err == nil {
+			t.Errorf("unmarshaled %s", payload)
+		}
+	}
+}
+`
+
+func TestEnums(t *testing.T) {
+	goTest(t, New("standard"), "enums", map[string]string{"enum_test.go": enumTest})
+}
+
+// strictEnumTest checks the Values, IsValid and Parse functions of the enums in testdata/enums and
+// that StrictEnums rejects strings that aren't members
+const strictEnumTest = `package standard
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestEnumValues(t *testing.T) {
+	if got := Enums_Color("").Values(); !slices.Equal(got, []Enums_Color{Enums_Color_Red, Enums_Color_Green}) {
+		t.Errorf("Color values = %v", got)
+	}
+	if got := Enums_Tiny(0).Values(); !slices.Equal(got, []Enums_Tiny{Enums_Tiny_A, Enums_Tiny_B}) {
+		t.Errorf("Tiny values = %v", got)
+	}
+	if !Enums_Color_Green.IsValid() || Enums_Color("Blue").IsValid() {
+		t.Error("IsValid of Color")
+	}
+	if !Enums_Big_Small.IsValid() || Enums_Big(2).IsValid() {
+		t.Error("IsValid of Big")
+	}
+	if !(Enums_Access_Read | Enums_Access_Execute).IsValid() || Enums_Access(8).IsValid() {
+		t.Error("IsValid of Access")
+	}
+	color, err := ParseEnums_Color("Green")
+	if err != nil || color != Enums_Color_Green {
+		t.Errorf("ParseEnums_Color = %s, %v", color, err)
+	}
+	_, err = ParseEnums_Color("green")
+	if err == nil {
+		t.Error("parsed a Color that isn't a member")
+	}
+	big, err := ParseEnums_Big("Huge")
+	if err != nil || big != Enums_Big_Huge {
+		t.Errorf("ParseEnums_Big = %s, %v", big, err)
+	}
+	access, err := ParseEnums_Access("Read,Write")
+	if err != nil || access != Enums_Access_Read|Enums_Access_Write {
+		t.Errorf("ParseEnums_Access = %s, %v", access, err)
+	}
+	_, err = ParseEnums_Access("Read,Delete")
+	if err == nil {
+		t.Error("parsed an Access that isn't a flag")
+	}
+}
+
+func TestStrictUnmarshal(t *testing.T) {
+	thing := Enums_Thing{}
+	err := json.Unmarshal([]byte(` + "`" + `{"Color":"Red"}` + "`" + `), &thing)
+	if err != nil || thing.Color == nil || *thing.Color != Enums_Color_Red {
+		t.Errorf("Color = %v, %v", thing.Color, err)
+	}
+	for _, payload := range []string{` + "`" + `{"Color":"Blue"}` + "`" + `, ` + "`" + `{"Color":1}` + "`" + `} {
+		err = json.Unmarshal([]byte(payload), &Enums_Thing{})
+		if err == nil {
+			t.Errorf("unmarshaled %s", payload)
+		}
+	}
+	thing = Enums_Thing{}
+	err = json.Unmarshal([]byte(` + "`" + `{"Color":null}` + "`" + `), &thing)
+	if err != nil || thing.Color != nil {
+		t.Errorf("null Color = %v, %v", thing.Color, err)
+	}
+}
+`
+
+// lenientEnumTest checks that string enums accept any string without StrictEnums
+const lenientEnumTest = `package standard
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestLenientUnmarshal(t *testing.T) {
+	thing := Enums_Thing{}
+	err := json.Unmarshal([]byte(` + "`" + `{"Color":"Blue"}` + "`" + `), &thing)
+	if err != nil || thing.Color == nil || *thing.Color != "Blue" || thing.Color.IsValid() {
+		t.Errorf("Color = %v, %v", thing.Color, err)
+	}
+}
+`
+
+func TestStrictEnums(t *testing.T) {
+	gen := New("standard")
+	gen.StrictEnums = true
+	goTest(t, gen, "enums", map[string]string{"enum_test.go": strictEnumTest})
+	goTest(t, New("standard"), "enums", map[string]string{"enum_test.go": lenientEnumTest})
+}
+
+// decodeTest checks that Decode picks the generated type from @odata.type, for versioned and
+// unversioned types, versions newer than the schema and collections
+const decodeTest = `package standard
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	value, err := Decode([]byte(` + "`" + `{"@odata.type": "#ComputerSystem.v1_1_0.ComputerSystem", "Id": "1", "HostName": "web"}` + "`" + `))
+	system, ok := value.(*ComputerSystem)
+	if err != nil || !ok || system.Id != "1" || system.HostName == nil || *system.HostName != "web" {
+		t.Errorf("Decode of a v1_1_0 ComputerSystem = %#v, %v", value, err)
+	}
+	for _, odataType := range []string{"#ComputerSystem.v1_20_0.ComputerSystem", "#ComputerSystem.ComputerSystem"} {
+		value, err = Decode([]byte(` + "`" + `{"@odata.type": "` + "`" + ` + odataType + ` + "`" + `", "Id": "2"}` + "`" + `))
+		system, ok = value.(*ComputerSystem)
+		if err != nil || !ok || system.Id != "2" {
+			t.Errorf("Decode of %s = %#v, %v", odataType, value, err)
+		}
+	}
+	value, err = Decode([]byte(` + "`" + `{"@odata.type": "#Resource.Resource", "Id": "3"}` + "`" + `))
+	resource, ok := value.(*Resource)
+	if err != nil || !ok || resource.Id != "3" {
+		t.Errorf("Decode of the abstract Resource = %#v, %v", value, err)
+	}
+	value, err = Decode([]byte(` + "`" + `{"@odata.type": "#ComputerSystemCollection.ComputerSystemCollection", "Members@odata.count": 1}` + "`" + `))
+	collection, ok := value.(*ComputerSystemCollection)
+	if err != nil || !ok || collection.MembersCount != 1 {
+		t.Errorf("Decode of a ComputerSystemCollection = %#v, %v", value, err)
+	}
+	_, err = Decode([]byte(` + "`" + `{"@odata.type": "#Manager.v1_0_0.Manager"}` + "`" + `))
+	if !errors.Is(err, ErrUnknownType) {
+		t.Errorf("Decode of an unknown type = %v, want ErrUnknownType", err)
+	}
+}
+
+func TestDecodeCollection(t *testing.T) {
+	value, err := Decode([]byte(` + "`" + `{"@odata.type": "#Collection(ComputerSystem.v1_0_0.ComputerSystem)", "value": [
+		{"Id": "1"},
+		{"@odata.type": "#Chassis.v1_0_0.Chassis", "Id": "2"}
+	]}` + "`" + `))
+	items, ok := value.([]any)
+	if err != nil || !ok || len(items) != 2 {
+		t.Fatalf("Decode of a collection = %#v, %v", value, err)
+	}
+	system, ok := items[0].(*ComputerSystem)
+	if !ok || system.Id != "1" {
+		t.Errorf("the item without an @odata.type = %#v, want a ComputerSystem", items[0])
+	}
+	chassis, ok := items[1].(*Chassis)
+	if !ok || chassis.Id != "2" {
+		t.Errorf("the Chassis item = %#v", items[1])
+	}
+	_, err = Decode([]byte(` + "`" + `{"@odata.type": "#Collection(Manager.v1_0_0.Manager)", "value": [{}]}` + "`" + `))
+	if !errors.Is(err, ErrUnknownType) {
+		t.Errorf("Decode of a collection of an unknown type = %v, want ErrUnknownType", err)
+	}
+}
+`
+
+func TestDecode(t *testing.T) {
+	goTest(t, New("standard"), "redfish", map[string]string{"decode_test.go": decodeTest})
+}
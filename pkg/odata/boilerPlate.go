@@ -6,175 +6,1344 @@ import (
 	"go/format"
 	"go/token"
 	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
 )
 
 const (
 	Filename = "odata.go"
 
 	DateTimeOffsetMarshalJSONText = `
-		func (d *DateTimeOffset) MarshalJSON() ([]byte, error) {
-			str := "\""+d.Time.Format(time.RFC3339)+"\""
-			return []byte(str), nil
+		// temporalString returns the contents of the JSON string for a date or time, null is false
+		// so that the value is left alone like encoding/json does for other types
+		func temporalString(b []byte, kind string) (string, bool, error) {
+			if string(b) == "null" {
+				return "", false, nil
+			}
+			str, err := strconv.Unquote(string(b))
+			if err != nil || b[0] != '"' {
+				return "", false, fmt.Errorf("invalid %s %s, expected a JSON string", kind, b)
+			}
+			return str, true, nil
+		}
+
+		// parseTime parses str with the first layout that fits it, the error is the one from the first
+		// layout
+		func parseTime(str string, layouts ...string) (time.Time, error) {
+			t, firstErr := time.Parse(layouts[0], str)
+			if firstErr == nil {
+				return t, nil
+			}
+			for _, layout := range layouts[1:] {
+				t, err := time.Parse(layout, str)
+				if err == nil {
+					return t, nil
+				}
+			}
+			return time.Time{}, firstErr
+		}
+
+		// dateTimeOffsetNoSeconds is RFC 3339 without the seconds, which CSDL allows
+		const dateTimeOffsetNoSeconds = "2006-01-02T15:04Z07:00"
+
+		// MarshalJSON writes the zero time as null
+		func (d DateTimeOffset) MarshalJSON() ([]byte, error) {
+			if d.Time.IsZero() {
+				return []byte("null"), nil
+			}
+			return []byte("\""+d.Time.Format(time.RFC3339Nano)+"\""), nil
 		}
 
 		func (d *DateTimeOffset) UnmarshalJSON(b []byte) error {
-			str := string(b)
-			t, err := time.Parse(time.RFC3339, str[1:len(str)-1])
+			str, ok, err := temporalString(b, "DateTimeOffset")
+			if !ok {
+				return err
+			}
+			if str == "" {
+				d.Time = time.Time{}
+				return nil
+			}
+			t, err := parseTime(str, time.RFC3339Nano, dateTimeOffsetNoSeconds)
 			if err != nil {
 				return err
 			}
 			d.Time = t
 			return nil
 		}
+
+		// MarshalJSON writes the zero date as null
+		func (d Date) MarshalJSON() ([]byte, error) {
+			if d.Time.IsZero() {
+				return []byte("null"), nil
+			}
+			return []byte("\""+d.Time.Format(time.DateOnly)+"\""), nil
+		}
+
+		func (d *Date) UnmarshalJSON(b []byte) error {
+			str, ok, err := temporalString(b, "Date")
+			if !ok {
+				return err
+			}
+			if str == "" {
+				d.Time = time.Time{}
+				return nil
+			}
+			t, err := time.Parse(time.DateOnly, str)
+			if err != nil {
+				return err
+			}
+			d.Time = t
+			return nil
+		}
+
+		// String returns the date as YYYY-MM-DD
+		func (d Date) String() string {
+			return d.Time.Format(time.DateOnly)
+		}
+
+		// timeOfDayLayout is hh:mm:ss with optional fractional seconds, the seconds are optional too
+		// when parsing
+		const timeOfDayLayout = "15:04:05.999999999"
+
+		// MarshalJSON always writes the time, the zero value is midnight
+		func (t TimeOfDay) MarshalJSON() ([]byte, error) {
+			return []byte("\""+t.String()+"\""), nil
+		}
+
+		func (t *TimeOfDay) UnmarshalJSON(b []byte) error {
+			str, ok, err := temporalString(b, "TimeOfDay")
+			if !ok {
+				return err
+			}
+			if str == "" {
+				t.Time = time.Time{}
+				return nil
+			}
+			parsed, err := parseTime(str, timeOfDayLayout, "15:04")
+			if err != nil {
+				return err
+			}
+			t.Time = parsed
+			return nil
+		}
+
+		// String returns the time as hh:mm:ss, with fractional seconds if there are any
+		func (t TimeOfDay) String() string {
+			return t.Time.Format(timeOfDayLayout)
+		}
 	`
 	DurationMarshalJSONText = `
 		type ParseError struct {
-			input []byte
+			input  []byte
+			reason string
 		}
 
 		func (e *ParseError) Error() string {
-			return "invalid duration \"" + string(e.input) + "\""
+			return "invalid duration \"" + string(e.input) + "\": " + e.reason
+		}
+
+		// durationDesignators are the components of a duration in the order they have to appear, the
+		// empty string separates the date part from the time part
+		var durationDesignators = []string{"Y", "M", "W", "D", "", "H", "M", "S"}
+
+		var durationUnits = map[string]time.Duration{
+			"W":  7 * 24 * time.Hour,
+			"D":  24 * time.Hour,
+			"TH": time.Hour,
+			"TM": time.Minute,
+			"TS": time.Second,
+		}
+
+		// ParseDuration parses an ISO 8601 duration, i.e. "P1Y2M3DT4H5M6.5S" or "-P2W". Years and
+		// months aren't a fixed length so they are kept in their own fields, weeks and days are 7 and
+		// 1 24 hour days in Duration. Any component but years and months can be fractional as long
+		// as it is the last one, and the fraction can use either . or , as the decimal separator.
+		func ParseDuration(s string) (Duration, error) {
+			d := Duration{}
+			input := s
+			negative := strings.HasPrefix(s, "-")
+			s = strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+			if !strings.HasPrefix(s, "P") || len(s) == 1 {
+				return d, &ParseError{[]byte(input), "it must start with P and have at least one component"}
+			}
+			s = s[1:]
+			next := 0
+			inTime := false
+			fractional := false
+			for s != "" {
+				if s[0] == 'T' {
+					if inTime || len(s) == 1 {
+						return d, &ParseError{[]byte(input), "T must be followed by hours, minutes, or seconds"}
+					}
+					inTime = true
+					next = 5
+					s = s[1:]
+					continue
+				}
+				if fractional {
+					return d, &ParseError{[]byte(input), "only the last component can have a fraction"}
+				}
+				end := strings.IndexFunc(s, func(r rune) bool { return (r < '0' || r > '9') && r != '.' && r != ',' })
+				if end <= 0 {
+					return d, &ParseError{[]byte(input), "expected a number at " + strconv.Quote(s)}
+				}
+				number := strings.Replace(s[:end], ",", ".", 1)
+				designator := s[end : end+1]
+				s = s[end+1:]
+				index := next
+				for index < len(durationDesignators) && (durationDesignators[index] != designator || (index >= 5) != inTime) {
+					index++
+				}
+				if index == len(durationDesignators) || (index >= 5) != inTime {
+					return d, &ParseError{[]byte(input), designator + " is out of order, repeated, or not a designator"}
+				}
+				next = index + 1
+				whole, fraction, hasFraction := strings.Cut(number, ".")
+				fractional = hasFraction
+				if whole == "" || (hasFraction && (fraction == "" || strings.ContainsAny(fraction, ".,"))) {
+					return d, &ParseError{[]byte(input), "invalid number " + strconv.Quote(number)}
+				}
+				value, err := strconv.ParseInt(whole, 10, 64)
+				if err != nil {
+					return d, &ParseError{[]byte(input), err.Error()}
+				}
+				if index < 2 {
+					// Years and months aren't a fixed length so they're kept separately
+					if hasFraction || value > math.MaxInt32 {
+						return d, &ParseError{[]byte(input), "years and months must be whole numbers"}
+					}
+					if index == 0 {
+						d.Years = int(value)
+					} else {
+						d.Months = int(value)
+					}
+					continue
+				}
+				unitName := designator
+				if inTime {
+					unitName = "T" + designator
+				}
+				unit := durationUnits[unitName]
+				if value > int64(math.MaxInt64-d.Duration)/int64(unit) {
+					return d, &ParseError{[]byte(input), "it is too long"}
+				}
+				d.Duration += time.Duration(value) * unit
+				if hasFraction {
+					fractionValue, _ := strconv.ParseFloat("0."+fraction, 64)
+					d.Duration += time.Duration(math.Round(fractionValue * float64(unit)))
+				}
+			}
+			if negative {
+				d.Years, d.Months, d.Duration = -d.Years, -d.Months, -d.Duration
+			}
+			return d, nil
 		}
 
-		// Not shared out side the package just an indicator that there was an error so that the
-		// parent function can return correctly
-		var errInternal = errors.New("internal error")
+		// String returns the ISO 8601 form of the duration, "PT0S" for zero. Years and months have to
+		// have the same sign as the rest of the duration, otherwise there is no ISO 8601 form.
+		func (d Duration) String() string {
+			str, err := d.format()
+			if err != nil {
+				return "%!Duration(" + err.Error() + ")"
+			}
+			return str
+		}
 
-		func (d *Duration) MarshalJSON() ([]byte, error) {
-			buf := bytes.NewBuffer(nil)
+		func (d Duration) format() (string, error) {
+			negative := d.Years < 0 || d.Months < 0 || d.Duration < 0
+			if negative && (d.Years > 0 || d.Months > 0 || d.Duration > 0) {
+				return "", errors.New("years, months, and the duration have different signs")
+			}
+			buf := strings.Builder{}
+			if negative {
+				buf.WriteString("-")
+			}
 			buf.WriteString("P")
-			hours := d.Duration.Hours()
-			if hours >= 24 {
-				days := int(hours) / 24
-				buf.WriteString(strconv.Itoa(days))
-				buf.WriteString("D")
-				hours = hours - float64(days*24)
+			years, months := d.Years, d.Months
+			if negative {
+				years, months = -years, -months
+			}
+			if years != 0 {
+				buf.WriteString(strconv.Itoa(years) + "Y")
+			}
+			if months != 0 {
+				buf.WriteString(strconv.Itoa(months) + "M")
 			}
-			if hours == 0 {
-				return buf.Bytes(), nil
+			// Work in unsigned nanoseconds so that the smallest time.Duration can be negated
+			remaining := uint64(d.Duration)
+			if negative {
+				remaining = -remaining
+			}
+			if years == 0 && months == 0 && remaining == 0 {
+				return "PT0S", nil
+			}
+			day := uint64(24 * time.Hour)
+			if remaining >= day {
+				buf.WriteString(strconv.FormatUint(remaining/day, 10) + "D")
+				remaining %= day
+			}
+			if remaining == 0 {
+				return buf.String(), nil
 			}
 			buf.WriteString("T")
-			hoursInt := int(hours)
-			if hoursInt > 0 {
-				buf.WriteString(strconv.Itoa(hoursInt))
-				buf.WriteString("H")
-			}
-			minutes := d.Duration.Minutes()
-			minutesInt := int(minutes)
-			if minutesInt > 0 && minutesInt%60 > 0 {
-				buf.WriteString(strconv.Itoa(minutesInt % 60))
-				buf.WriteString("M")
-			}
-			seconds := d.Duration.Seconds()
-			seconds = seconds - float64(minutesInt*60)
-			if seconds > 0 {
-				buf.WriteString(strconv.FormatFloat(seconds, 'f', -1, 64))
+			if remaining >= uint64(time.Hour) {
+				buf.WriteString(strconv.FormatUint(remaining/uint64(time.Hour), 10) + "H")
+				remaining %= uint64(time.Hour)
+			}
+			if remaining >= uint64(time.Minute) {
+				buf.WriteString(strconv.FormatUint(remaining/uint64(time.Minute), 10) + "M")
+				remaining %= uint64(time.Minute)
+			}
+			if remaining != 0 {
+				buf.WriteString(strconv.FormatUint(remaining/uint64(time.Second), 10))
+				nanoseconds := remaining % uint64(time.Second)
+				if nanoseconds != 0 {
+					fraction := strconv.FormatUint(nanoseconds+uint64(time.Second), 10)[1:]
+					buf.WriteString("." + strings.TrimRight(fraction, "0"))
+				}
 				buf.WriteString("S")
 			}
-			return buf.Bytes(), nil
+			return buf.String(), nil
+		}
+
+		func (d Duration) MarshalText() ([]byte, error) {
+			str, err := d.format()
+			return []byte(str), err
+		}
+
+		func (d *Duration) UnmarshalText(text []byte) error {
+			parsed, err := ParseDuration(string(text))
+			if err != nil {
+				return err
+			}
+			*d = parsed
+			return nil
+		}
+
+		func (d Duration) MarshalJSON() ([]byte, error) {
+			str, err := d.format()
+			if err != nil {
+				return nil, err
+			}
+			return []byte("\"" + str + "\""), nil
 		}
 
 		func (d *Duration) UnmarshalJSON(b []byte) error {
-			if bytes.Equal([]byte("null"), b) {
+			str, ok, err := temporalString(b, "Duration")
+			if !ok {
+				return err
+			}
+			if str == "" {
+				*d = Duration{}
 				return nil
 			}
-			b = bytes.Trim(b, "\"")
-			// Per Redfish Spec: No negative values...
-			if b[0] != 'P' {
-				return &ParseError{b}
+			return d.UnmarshalText([]byte(str))
+		}
+	`
+
+	CollectionText = `
+		// Collection is a Redfish resource collection whose members are of type T, usually OdataID
+		// or an expanded member type
+		type Collection[T any] struct {
+			ID              string  ` + "`json:\"@odata.id\"`" + `
+			Type            string  ` + "`json:\"@odata.type,omitempty\"`" + `
+			Context         string  ` + "`json:\"@odata.context,omitempty\"`" + `
+			Name            string
+			Description     *string ` + "`json:\",omitempty\"`" + `
+			Members         []T     ` + "`json:\"Members\"`" + `
+			MembersCount    int64   ` + "`json:\"Members@odata.count\"`" + `
+			MembersNextLink string  ` + "`json:\"Members@odata.nextLink,omitempty\"`" + `
+		}
+
+		// HasNextPage returns true if the service returned only part of the collection
+		func (c *Collection[T]) HasNextPage() bool {
+			return c.MembersNextLink != ""
+		}
+
+		// All follows the next links using fetch until every member of the collection has been retrieved,
+		// a nil page from fetch ends the collection
+		func (c *Collection[T]) All(fetch func(nextLink string) (*Collection[T], error)) ([]T, error) {
+			members := append([]T{}, c.Members...)
+			nextLink := c.MembersNextLink
+			for nextLink != "" {
+				page, err := fetch(nextLink)
+				if err != nil || page == nil {
+					return members, err
+				}
+				members = append(members, page.Members...)
+				nextLink = page.MembersNextLink
 			}
-			days, rest, err := getDays(b[1:])
-			if err != nil {
-				return &ParseError{b}
+			return members, nil
+		}
+	`
+
+	ValidationText = `
+		// ValidationError is a value that doesn't meet a constraint from the schema
+		type ValidationError struct {
+			// Path is a JSON pointer to the value, i.e. "/ProcessorSummary/Count"
+			Path    string
+			Message string
+		}
+
+		func (e *ValidationError) Error() string {
+			return e.Path + ": " + e.Message
+		}
+
+		// ValidationErrors is every constraint violation found by Validate
+		type ValidationErrors []*ValidationError
+
+		func (e ValidationErrors) Error() string {
+			messages := make([]string, 0, len(e))
+			for _, err := range e {
+				messages = append(messages, err.Error())
+			}
+			return strings.Join(messages, "; ")
+		}
+
+		// Unwrap allows errors.As to find the individual ValidationError values
+		func (e ValidationErrors) Unwrap() []error {
+			errs := make([]error, 0, len(e))
+			for _, err := range e {
+				errs = append(errs, err)
 			}
-			d.Duration = time.Duration(days*24*int(time.Hour))
-			if len(rest) == 0 {
+			return errs
+		}
+
+		// Err returns nil if there are no violations, otherwise the ValidationErrors
+		func (e *ValidationErrors) Err() error {
+			if len(*e) == 0 {
 				return nil
 			}
-			// Next character must be "T"
-			if rest[0] != 'T' {
-				return &ParseError{b}
+			return *e
+		}
+
+		func (e *ValidationErrors) add(path string, message string) {
+			*e = append(*e, &ValidationError{Path: path, Message: message})
+		}
+
+		func (e *ValidationErrors) checkRequired(path string, present bool) {
+			if !present {
+				e.add(path, "required property is missing")
+			}
+		}
+
+		// Patterns are compiled the first time they are used
+		var patterns sync.Map
+
+		func (e *ValidationErrors) checkPattern(path string, value string, pattern string) {
+			re, ok := patterns.Load(pattern)
+			if !ok {
+				re, _ = patterns.LoadOrStore(pattern, regexp.MustCompile(pattern))
+			}
+			if !re.(*regexp.Regexp).MatchString(value) {
+				e.add(path, fmt.Sprintf("%q does not match the pattern %q", value, pattern))
+			}
+		}
+
+		func (e *ValidationErrors) checkMinimum(path string, value float64, minimum float64) {
+			if value < minimum {
+				e.add(path, fmt.Sprintf("%v is less than the minimum %v", value, minimum))
+			}
+		}
+
+		func (e *ValidationErrors) checkMaximum(path string, value float64, maximum float64) {
+			if value > maximum {
+				e.add(path, fmt.Sprintf("%v is greater than the maximum %v", value, maximum))
 			}
-			hours, rest, err := getHours(rest[1:])
+		}
+
+		func jsonIndex(path string, index int) string {
+			return path + "/" + strconv.Itoa(index)
+		}
+	`
+
+	PermissionText = `
+		// Permission is the OData.Permissions of a property
+		type Permission string
+
+		const (
+			Permission_None      Permission = "None"
+			Permission_Read      Permission = "Read"
+			Permission_Write     Permission = "Write"
+			Permission_ReadWrite Permission = "ReadWrite"
+		)
+
+		// Writable returns true if the property can be changed with a PATCH
+		func (p Permission) Writable() bool {
+			return p == Permission_Write || p == Permission_ReadWrite
+		}
+	`
+
+	// ClientText is the client layer that typed links use, only generated when asked for
+	ClientText = `
+		// Client is what links use to fetch resources, HTTPClient is the usual implementation
+		type Client interface {
+			// Get fetches the resource at path, i.e. "/redfish/v1/Systems/1", and unmarshals it into v
+			Get(ctx context.Context, path string, v any) error
+		}
+
+		// Transport sends requests to the service, *http.Client is one
+		type Transport interface {
+			Do(req *http.Request) (*http.Response, error)
+		}
+
+		// Authenticator adds credentials to each request
+		type Authenticator interface {
+			Authenticate(req *http.Request) error
+		}
+
+		// BasicAuth authenticates with HTTP basic authentication
+		type BasicAuth struct {
+			Username string
+			Password string
+		}
+
+		func (b BasicAuth) Authenticate(req *http.Request) error {
+			req.SetBasicAuth(b.Username, b.Password)
+			return nil
+		}
+
+		// SessionAuth authenticates with the X-Auth-Token of a Redfish session
+		type SessionAuth struct {
+			Token string
+		}
+
+		func (s SessionAuth) Authenticate(req *http.Request) error {
+			req.Header.Set("X-Auth-Token", s.Token)
+			return nil
+		}
+
+		// HTTPClient fetches resources from a Redfish service
+		type HTTPClient struct {
+			BaseURL   string        // scheme and host, i.e. "https://bmc.example.com"
+			Transport Transport     // http.DefaultClient if nil
+			Auth      Authenticator // no credentials are sent if nil
+		}
+
+		func NewHTTPClient(baseURL string, transport Transport, auth Authenticator) *HTTPClient {
+			return &HTTPClient{
+				BaseURL:   baseURL,
+				Transport: transport,
+				Auth:      auth,
+			}
+		}
+
+		// HTTPError is returned for unsuccessful responses, Body is usually a Redfish error response
+		type HTTPError struct {
+			StatusCode int
+			Body       []byte
+		}
+
+		func (e *HTTPError) Error() string {
+			return fmt.Sprintf("unexpected status %d: %s", e.StatusCode, e.Body)
+		}
+
+		func (c *HTTPClient) Get(ctx context.Context, path string, v any) error {
+			url := path
+			if !strings.HasPrefix(path, "http://") && !strings.HasPrefix(path, "https://") {
+				url = strings.TrimSuffix(c.BaseURL, "/") + path
+			}
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 			if err != nil {
-				return &ParseError{b}
+				return err
 			}
-			d.Duration += time.Duration(hours * int(time.Hour))
-			if len(rest) == 0 {
-				return nil
+			req.Header.Set("Accept", "application/json")
+			if c.Auth != nil {
+				err = c.Auth.Authenticate(req)
+				if err != nil {
+					return err
+				}
+			}
+			var transport Transport = http.DefaultClient
+			if c.Transport != nil {
+				transport = c.Transport
+			}
+			resp, err := transport.Do(req)
+			if err != nil {
+				return err
 			}
-			mins, rest, err := getMinutes(rest)
+			//nolint:errcheck // Ignore error on close, the body has already been read
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
 			if err != nil {
-				return &ParseError{b}
+				return err
 			}
-			d.Duration += time.Duration(mins * int(time.Minute))
-			if len(rest) == 0 {
-				return nil
+			if resp.StatusCode < 200 || resp.StatusCode > 299 {
+				return &HTTPError{StatusCode: resp.StatusCode, Body: body}
+			}
+			return json.Unmarshal(body, v)
+		}
+
+		// Link is a navigation property that can be followed to the T it refers to
+		type Link[T any] struct {
+			ID string ` + "`json:\"@odata.id\"`" + `
+		}
+
+		// Get fetches the resource the link refers to
+		func (l Link[T]) Get(ctx context.Context, c Client) (*T, error) {
+			if l.ID == "" {
+				return nil, errors.New("the link has no @odata.id")
+			}
+			return Fetch[T](ctx, c, l.ID)
+		}
+
+		// Fetch gets the resource at path, i.e. Fetch[ServiceRoot](ctx, c, "/redfish/v1")
+		func Fetch[T any](ctx context.Context, c Client, path string) (*T, error) {
+			ret := new(T)
+			err := c.Get(ctx, path, ret)
+			if err != nil {
+				return nil, err
+			}
+			return ret, nil
+		}
+	`
+
+	// ServerText is the router that generated resource handlers register with, only generated when
+	// asked for
+	ServerText = `
+		// Request is aliased so that the generated resource files don't need imports
+		type Request = http.Request
+
+		// serveMux is the http.ServeMux the routes register with. Templates that only differ in the
+		// fixed text around a parameter, like {Id}.json and {Id}.xml, have the same ServeMux pattern,
+		// so they share one handler that serves the first template the URI matches.
+		type serveMux struct {
+			mux      *http.ServeMux
+			patterns map[string][]route
+		}
+
+		type route struct {
+			uri     URITemplate
+			handler http.HandlerFunc
+		}
+
+		// routes are added by the init function of each resource with URIs
+		var routes []func(mux *serveMux, server any)
+
+		// NewRouter returns a handler that serves GET for every resource server implements the
+		// <Resource>Getter interface for. PATCH, DELETE, and POST are served if server implements
+		// the matching <Resource>Patcher, <Resource>Deleter, or <Collection>Poster interface.
+		// Handlers get the URI's parameters, like {ChassisId}, from Request.PathValue.
+		func NewRouter(server any) *http.ServeMux {
+			mux := &serveMux{mux: http.NewServeMux(), patterns: map[string][]route{}}
+			for _, route := range routes {
+				route(mux, server)
 			}
-			seconds, fraction, err := getSeconds(rest)
+			return mux.mux
+		}
+
+		// StatusError lets a handler pick the HTTP status of an error, other errors are a 500
+		type StatusError struct {
+			StatusCode int
+			Err        error
+		}
+
+		func (e *StatusError) Error() string {
+			return e.Err.Error()
+		}
+
+		func (e *StatusError) Unwrap() error {
+			return e.Err
+		}
+
+		// writeError writes err as a Redfish error response
+		func writeError(w http.ResponseWriter, err error) {
+			status := http.StatusInternalServerError
+			var statusErr *StatusError
+			if errors.As(err, &statusErr) {
+				status = statusErr.StatusCode
+			}
+			writeJSON(w, status, map[string]any{
+				"error": map[string]any{
+					"code":    "Base.1.0.GeneralError",
+					"message": err.Error(),
+				},
+			})
+		}
+
+		func writeJSON(w http.ResponseWriter, status int, v any) {
+			body, err := json.Marshal(v)
 			if err != nil {
-				return &ParseError{b}
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
 			}
-			d.Duration += time.Duration(seconds*int(time.Second) + int(fraction*float64(time.Second)))
-			return nil
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			_, _ = w.Write(body)
 		}
 
-		func getType(input []byte, char rune) (int, []byte, error) {
-			index := bytes.IndexRune(input, char)
-			if index == -1 {
-				// This is fine, only seconds are required (if we get that far)
-				return 0, input, nil
+		// handle registers handler for the method and URI. ServeMux wildcards have to be a whole
+		// segment, so every segment with a parameter, like {Id}.json, is registered as a wildcard
+		// and the URI is matched against the templates for the pattern to set the path values.
+		func handle(mux *serveMux, method string, uri URITemplate, handler http.HandlerFunc) {
+			if strings.Contains(string(uri), "#") {
+				// Members of a resource, like #/PowerControl/{PowerControlId}, are served in its
+				// payload since the fragment isn't sent to the server
+				return
+			}
+			segments := strings.Split(string(uri), "/")
+			for i, segment := range segments {
+				if strings.Contains(segment, "{") {
+					segments[i] = "{segment" + strconv.Itoa(i) + "}"
+				}
+			}
+			pattern := method + " " + strings.Join(segments, "/")
+			if strings.HasSuffix(pattern, "/") {
+				pattern += "{$}"
+			}
+			registered, ok := mux.patterns[pattern]
+			mux.patterns[pattern] = append(registered, route{uri: uri, handler: handler})
+			if ok {
+				return
+			}
+			mux.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+				for _, route := range mux.patterns[pattern] {
+					values, ok := route.uri.Match(r.URL.EscapedPath())
+					if !ok {
+						continue
+					}
+					for name, value := range values {
+						r.SetPathValue(name, value)
+					}
+					route.handler(w, r)
+					return
+				}
+				writeError(w, &StatusError{StatusCode: http.StatusNotFound, Err: fmt.Errorf("%s not found", r.URL.Path)})
+			})
+		}
+
+		// readBody decodes and validates a request body, writing the error response if it isn't valid
+		func readBody[B any](w http.ResponseWriter, r *http.Request) (*B, bool) {
+			body := new(B)
+			err := json.NewDecoder(r.Body).Decode(body)
+			if err == nil {
+				validator, ok := any(body).(interface{ Validate() error })
+				if ok {
+					err = validator.Validate()
+				}
 			}
-			ret, err := strconv.Atoi(string(input[:index]))
 			if err != nil {
-				return 0, nil, err
+				writeError(w, &StatusError{StatusCode: http.StatusBadRequest, Err: err})
+				return nil, false
+			}
+			return body, true
+		}
+
+		func handleGet[T any](mux *serveMux, uri URITemplate, get func(*Request) (*T, error)) {
+			handle(mux, http.MethodGet, uri, func(w http.ResponseWriter, r *http.Request) {
+				resource, err := get(r)
+				if err == nil && resource == nil {
+					err = &StatusError{StatusCode: http.StatusNotFound, Err: fmt.Errorf("%s not found", r.URL.Path)}
+				}
+				if err != nil {
+					writeError(w, err)
+					return
+				}
+				writeJSON(w, http.StatusOK, resource)
+			})
+		}
+
+		func handleResource[T any, P any](mux *serveMux, uri URITemplate, get func(*Request) (*T, error), patch func(*Request, *P) (*T, error), del func(*Request) error) {
+			handleGet(mux, uri, get)
+			if patch != nil {
+				handle(mux, http.MethodPatch, uri, func(w http.ResponseWriter, r *http.Request) {
+					body, ok := readBody[P](w, r)
+					if !ok {
+						return
+					}
+					resource, err := patch(r, body)
+					if err != nil {
+						writeError(w, err)
+						return
+					}
+					writeJSON(w, http.StatusOK, resource)
+				})
+			}
+			if del != nil {
+				handle(mux, http.MethodDelete, uri, func(w http.ResponseWriter, r *http.Request) {
+					err := del(r)
+					if err != nil {
+						writeError(w, err)
+						return
+					}
+					w.WriteHeader(http.StatusNoContent)
+				})
+			}
+		}
+
+		func handleCollection[T any, B any, M any](mux *serveMux, uri URITemplate, get func(*Request) (*T, error), post func(*Request, *B) (*M, error)) {
+			handleGet(mux, uri, get)
+			if post == nil {
+				return
 			}
-			return ret, input[index+1:], nil
+			handle(mux, http.MethodPost, uri, func(w http.ResponseWriter, r *http.Request) {
+				body, ok := readBody[B](w, r)
+				if !ok {
+					return
+				}
+				member, err := post(r, body)
+				if err != nil {
+					writeError(w, err)
+					return
+				}
+				// The new member's URI is its @odata.id
+				created, err := json.Marshal(member)
+				if err == nil {
+					id := OdataID{}
+					err = json.Unmarshal(created, &id)
+					if err == nil && id.ID != "" {
+						w.Header().Set("Location", id.ID)
+					}
+				}
+				writeJSON(w, http.StatusCreated, member)
+			})
 		}
+	`
 
-		func getDays(input []byte) (int, []byte, error) {
-			return getType(input, 'D')
+	URIText = `
+		// URITemplate is a URI from Redfish.Uris, the segments in braces like {ChassisId} are
+		// parameters
+		type URITemplate string
+
+		// Params returns the names of the parameters in the order they appear
+		func (u URITemplate) Params() []string {
+			params := []string{}
+			rest := string(u)
+			for {
+				start := strings.Index(rest, "{")
+				end := strings.Index(rest, "}")
+				if start == -1 || end < start {
+					return params
+				}
+				params = append(params, rest[start+1:end])
+				rest = rest[end+1:]
+			}
 		}
 
-		func getHours(input []byte) (int, []byte, error) {
-			return getType(input, 'H')
+		// Expand returns the URI with the parameters replaced in order by the path escaped values,
+		// parameters without a value are left as they are
+		func (u URITemplate) Expand(values ...string) string {
+			buf := strings.Builder{}
+			rest := string(u)
+			for _, value := range values {
+				start := strings.Index(rest, "{")
+				end := strings.Index(rest, "}")
+				if start == -1 || end < start {
+					break
+				}
+				buf.WriteString(rest[:start])
+				buf.WriteString(url.PathEscape(value))
+				rest = rest[end+1:]
+			}
+			buf.WriteString(rest)
+			return buf.String()
 		}
 
-		func getMinutes(input []byte) (int, []byte, error) {
-			return getType(input, 'M')
+		// Match returns the values of the parameters if uri is an instance of the template. The path
+		// and the fragment, like #/PowerControl/{PowerControlId} in the URIs of members of a resource,
+		// are matched separately. The query and a trailing slash are ignored.
+		func (u URITemplate) Match(uri string) (map[string]string, bool) {
+			uri, fragment, _ := strings.Cut(uri, "#")
+			uri, _, _ = strings.Cut(uri, "?")
+			templatePath, templateFragment, _ := strings.Cut(string(u), "#")
+			values := map[string]string{}
+			if !matchSegments(templatePath, uri, values) || !matchSegments(templateFragment, fragment, values) {
+				return nil, false
+			}
+			return values, true
 		}
 
-		func getSeconds(input []byte) (int, float64, error) {
-			// This one is different, if we got here and there is no S that is an error
-			index := bytes.IndexRune(input, 'S')
-			if index == -1 || index != len(input)-1 {
-				return 0, 0, errInternal
+		// matchSegments matches the / separated segments of a path or fragment against the ones in a
+		// template, adding the values of the parameters to values
+		func matchSegments(template string, uri string, values map[string]string) bool {
+			templateSegments := strings.Split(strings.TrimSuffix(template, "/"), "/")
+			uriSegments := strings.Split(strings.TrimSuffix(uri, "/"), "/")
+			if len(templateSegments) != len(uriSegments) {
+				return false
 			}
-			// Seconds can have a fractional part, so check if we have that...
-			fractionIndex := bytes.IndexRune(input, '.')
-			if fractionIndex == -1 {
-				// This is fine, no fractional seconds is valid
-				seconds, err := strconv.Atoi(string(input[:index]))
+			for i, segment := range templateSegments {
+				start := strings.Index(segment, "{")
+				end := strings.LastIndex(segment, "}")
+				if start == -1 || end < start {
+					if segment != uriSegments[i] {
+						return false
+					}
+					continue
+				}
+				// A parameter can share its segment with fixed text, i.e. {ChassisId}.json
+				prefix, suffix := segment[:start], segment[end+1:]
+				value := uriSegments[i]
+				if len(value) <= len(prefix)+len(suffix) || !strings.HasPrefix(value, prefix) || !strings.HasSuffix(value, suffix) {
+					return false
+				}
+				value, err := url.PathUnescape(value[len(prefix) : len(value)-len(suffix)])
 				if err != nil {
-					return 0, 0, err
+					return false
 				}
-				return seconds, 0, nil
+				values[segment[start+1:end]] = value
 			}
-			floatVal, err := strconv.ParseFloat(string(input[:index]), 64)
+			return true
+		}
+
+		// matchURIs returns the parameters of the first template that uri matches
+		func matchURIs(templates []URITemplate, uri string) (map[string]string, bool) {
+			for _, template := range templates {
+				values, ok := template.Match(uri)
+				if ok {
+					return values, true
+				}
+			}
+			return nil, false
+		}
+	`
+
+	FieldVersionsText = `
+		// fieldVersions are the _FieldVersions of the generated types by their qualified name without
+		// the version, like Chassis.Chassis
+		var fieldVersions = map[string]map[string]string{}
+
+		// ValidFields returns the fields of the type an @odata.type names, like "#Chassis.v1_5_0.Chassis",
+		// that are defined in that version of the schema, by their names in the payload like
+		// "Members@odata.count". Every field is valid for an unversioned type. It is false if the type
+		// wasn't generated.
+		func ValidFields(odataType string) ([]string, bool) {
+			typeName := strings.TrimPrefix(odataType, "#")
+			unversioned := unversionedType(typeName)
+			version := ""
+			if unversioned != typeName {
+				namespace, _, _ := cutLast(typeName, ".")
+				_, version, _ = cutLast(namespace, ".")
+			}
+			versions, ok := fieldVersions[unversioned]
+			if !ok {
+				return nil, false
+			}
+			fields := []string{}
+			for field, since := range versions {
+				if since == "" || version == "" || compareVersions(since, version) <= 0 {
+					fields = append(fields, field)
+				}
+			}
+			slices.Sort(fields)
+			return fields, true
+		}
+
+		// compareVersions orders schema versions like v1_10_0 by number
+		func compareVersions(a string, b string) int {
+			aParts := strings.Split(strings.TrimPrefix(a, "v"), "_")
+			bParts := strings.Split(strings.TrimPrefix(b, "v"), "_")
+			for i := 0; i < len(aParts) && i < len(bParts); i++ {
+				aNumber, _ := strconv.Atoi(aParts[i])
+				bNumber, _ := strconv.Atoi(bParts[i])
+				if aNumber != bNumber {
+					return aNumber - bNumber
+				}
+			}
+			return len(aParts) - len(bParts)
+		}
+	`
+
+	DecodeText = `
+		// ErrUnknownType is returned by Decode for a payload whose @odata.type isn't one of the generated
+		// types
+		var ErrUnknownType = errors.New("unknown @odata.type")
+
+		// odataTypes creates the generated types by @odata.type, like "#Chassis.v1_0_0.Chassis", the
+		// generated files register them
+		var odataTypes = map[string]func() any{}
+
+		func newType[T any]() any {
+			return new(T)
+		}
+
+		func registerTypes(types map[string]func() any) {
+			for odataType, newFunc := range types {
+				odataTypes[odataType] = newFunc
+			}
+		}
+
+		// Decode unmarshals a payload into a pointer to the generated type its @odata.type names, so a
+		// derived type is decoded as itself rather than as its base type. A version newer than the ones
+		// that were generated is decoded as the newest one.
+		//
+		// A "#Collection(...)" type is either a JSON array or an object with the items in value, it is
+		// decoded into a []any with each item decoded by its own @odata.type, or the type in the
+		// parentheses for the items that don't have one.
+		func Decode(data []byte) (any, error) {
+			payload := struct {
+				Type string ` + "`" + `json:"@odata.type"` + "`" + `
+			}{}
+			err := json.Unmarshal(data, &payload)
+			if err != nil {
+				return nil, err
+			}
+			return decodeAs(payload.Type, data)
+		}
+
+		func decodeAs(odataType string, data []byte) (any, error) {
+			itemType, isCollection := strings.CutPrefix(odataType, "#Collection(")
+			if isCollection {
+				return decodeCollection("#"+strings.TrimSuffix(itemType, ")"), data)
+			}
+			newFunc, ok := odataTypes[odataType]
+			if !ok {
+				newFunc, ok = odataTypes[unversionedType(odataType)]
+			}
+			if !ok {
+				return nil, fmt.Errorf("%w %q", ErrUnknownType, odataType)
+			}
+			value := newFunc()
+			err := json.Unmarshal(data, value)
+			if err != nil {
+				return nil, err
+			}
+			return value, nil
+		}
+
+		func decodeCollection(itemType string, data []byte) (any, error) {
+			items := []json.RawMessage{}
+			err := json.Unmarshal(data, &items)
+			if err != nil {
+				collection := struct {
+					Value []json.RawMessage ` + "`" + `json:"value"` + "`" + `
+				}{}
+				if json.Unmarshal(data, &collection) != nil {
+					return nil, err
+				}
+				items = collection.Value
+			}
+			values := make([]any, 0, len(items))
+			for _, item := range items {
+				payload := struct {
+					Type string ` + "`" + `json:"@odata.type"` + "`" + `
+				}{Type: itemType}
+				err = json.Unmarshal(item, &payload)
+				if err != nil {
+					return nil, err
+				}
+				value, err := decodeAs(payload.Type, item)
+				if err != nil {
+					return nil, err
+				}
+				values = append(values, value)
+			}
+			return values, nil
+		}
+
+		// unversionedType removes the version from an @odata.type, so "#Chassis.v1_26_0.Chassis" is
+		// "#Chassis.Chassis"
+		func unversionedType(odataType string) string {
+			namespace, name, ok := cutLast(odataType, ".")
+			if !ok {
+				return odataType
+			}
+			prefix, version, ok := cutLast(namespace, ".")
+			if !ok || !isVersion(version) {
+				return odataType
+			}
+			return prefix + "." + name
+		}
+
+		// isVersion returns true for the version of a namespace, like v1_2_0
+		func isVersion(s string) bool {
+			digits := strings.TrimPrefix(s, "v")
+			return len(digits) != 0 && len(digits) != len(s) && strings.Trim(digits, "0123456789_") == ""
+		}
+
+		func cutLast(s string, sep string) (string, string, bool) {
+			index := strings.LastIndex(s, sep)
+			if index == -1 {
+				return s, "", false
+			}
+			return s[:index], s[index+len(sep):], true
+		}
+	`
+
+	EnumText = `
+		// enumInt is the underlying type of an integer backed enum
+		type enumInt interface {
+			~uint8 | ~int8 | ~int16 | ~int32 | ~int64
+		}
+
+		// enumMember is the name and value of a member of an integer backed enum
+		type enumMember[T enumInt] struct {
+			name  string
+			value T
+		}
+
+		func enumString[T enumInt](members []enumMember[T], value T) string {
+			for _, member := range members {
+				if member.value == value {
+					return member.name
+				}
+			}
+			return strconv.FormatInt(int64(value), 10)
+		}
+
+		func enumValues[T enumInt](members []enumMember[T]) []T {
+			values := make([]T, 0, len(members))
+			for _, member := range members {
+				values = append(values, member.value)
+			}
+			return values
+		}
+
+		func isEnumMember[T enumInt](members []enumMember[T], value T) bool {
+			for _, member := range members {
+				if member.value == value {
+					return true
+				}
+			}
+			return false
+		}
+
+		func marshalEnum[T enumInt](members []enumMember[T], value T) ([]byte, error) {
+			for _, member := range members {
+				if member.value == value {
+					return []byte(member.name), nil
+				}
+			}
+			return nil, fmt.Errorf("invalid %T %d", value, value)
+		}
+
+		func unmarshalEnum[T enumInt](members []enumMember[T], value *T, text []byte) error {
+			for _, member := range members {
+				if member.name == string(text) {
+					*value = member.value
+					return nil
+				}
+			}
+			return fmt.Errorf("invalid %T %q", *value, text)
+		}
+
+		// flagNames returns the names of the flags that make up value along with the bits that
+		// aren't a flag. A flag that combines others is only used if they aren't listed first.
+		func flagNames[T enumInt](members []enumMember[T], value T) ([]string, T) {
+			names := []string{}
+			if value == 0 {
+				for _, member := range members {
+					if member.value == 0 {
+						names = append(names, member.name)
+						break
+					}
+				}
+				return names, 0
+			}
+			remaining := value
+			for _, member := range members {
+				if member.value != 0 && remaining&member.value == member.value {
+					names = append(names, member.name)
+					remaining &^= member.value
+				}
+			}
+			return names, remaining
+		}
+
+		func validFlags[T enumInt](members []enumMember[T], value T) bool {
+			_, remaining := flagNames(members, value)
+			return remaining == 0
+		}
+
+		func flagsString[T enumInt](members []enumMember[T], value T) string {
+			names, remaining := flagNames(members, value)
+			if remaining != 0 {
+				names = append(names, strconv.FormatInt(int64(remaining), 10))
+			}
+			return strings.Join(names, ",")
+		}
+
+		func marshalFlags[T enumInt](members []enumMember[T], value T) ([]byte, error) {
+			names, remaining := flagNames(members, value)
+			if remaining != 0 {
+				return nil, fmt.Errorf("invalid %T %d, %d isn't a flag", value, value, remaining)
+			}
+			return []byte(strings.Join(names, ",")), nil
+		}
+
+		func unmarshalFlags[T enumInt](members []enumMember[T], value *T, text []byte) error {
+			flags := T(0)
+			for _, name := range strings.Split(string(text), ",") {
+				name = strings.TrimSpace(name)
+				if name == "" {
+					continue
+				}
+				found := false
+				for _, member := range members {
+					if member.name == name {
+						flags |= member.value
+						found = true
+						break
+					}
+				}
+				if !found {
+					return fmt.Errorf("invalid %T %q, %s isn't a flag", *value, text, name)
+				}
+			}
+			*value = flags
+			return nil
+		}
+
+		func isEnumValue[T ~string](values []T, value T) bool {
+			for _, v := range values {
+				if v == value {
+					return true
+				}
+			}
+			return false
+		}
+
+		func parseEnum[T ~string](values []T, text string) (T, error) {
+			value := T(text)
+			if !isEnumValue(values, value) {
+				return "", fmt.Errorf("invalid %T %q", value, text)
+			}
+			return value, nil
+		}
+
+		// unmarshalStrictEnum is UnmarshalJSON for a string enum that only accepts its members, null is
+		// left alone like encoding/json does for other types
+		func unmarshalStrictEnum[T ~string](values []T, value *T, b []byte) error {
+			if string(b) == "null" {
+				return nil
+			}
+			text, err := strconv.Unquote(string(b))
+			if err != nil || b[0] != '"' {
+				return fmt.Errorf("invalid %T %s, expected a JSON string", *value, b)
+			}
+			parsed, err := parseEnum(values, text)
 			if err != nil {
-				return 0, 0, err
+				return err
 			}
-			return int(floatVal), float64(floatVal - float64(int(floatVal))), nil
+			*value = parsed
+			return nil
 		}
 	`
 
 	UUIDMarshalJSONText = `
-		func (u *UUID) MarshalJSON() ([]byte, error) {
+		// ParseUUID parses the canonical form of a UUID, i.e. "123e4567-e89b-12d3-a456-426614174000",
+		// in either case
+		func ParseUUID(s string) (UUID, error) {
+			u := UUID{}
+			if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+				return u, fmt.Errorf("invalid UUID %q", s)
+			}
+			_, err := hex.Decode(u[:], []byte(s[0:8]+s[9:13]+s[14:18]+s[19:23]+s[24:36]))
+			if err != nil {
+				return UUID{}, fmt.Errorf("invalid UUID %q", s)
+			}
+			return u, nil
+		}
+
+		// String returns the canonical lower case form of the UUID
+		func (u UUID) String() string {
+			buf := make([]byte, 36)
+			hex.Encode(buf[0:8], u[0:4])
+			buf[8] = '-'
+			hex.Encode(buf[9:13], u[4:6])
+			buf[13] = '-'
+			hex.Encode(buf[14:18], u[6:8])
+			buf[18] = '-'
+			hex.Encode(buf[19:23], u[8:10])
+			buf[23] = '-'
+			hex.Encode(buf[24:], u[10:])
+			return string(buf)
+		}
+
+		// IsZero returns true for the nil UUID, all zeros
+		func (u UUID) IsZero() bool {
+			return u == UUID{}
+		}
+
+		func (u UUID) MarshalText() ([]byte, error) {
+			return []byte(u.String()), nil
+		}
+
+		func (u *UUID) UnmarshalText(text []byte) error {
+			parsed, err := ParseUUID(string(text))
+			if err != nil {
+				return err
+			}
+			*u = parsed
+			return nil
+		}
+
+		func (u UUID) MarshalJSON() ([]byte, error) {
+			return []byte("\"" + u.String() + "\""), nil
+		}
+
+		// UnmarshalJSON leaves the UUID alone for null, like encoding/json does for other types
+		func (u *UUID) UnmarshalJSON(b []byte) error {
+			if string(b) == "null" {
+				return nil
+			}
+			str, err := strconv.Unquote(string(b))
+			if err != nil {
+				return fmt.Errorf("invalid UUID %s", b)
+			}
+			return u.UnmarshalText([]byte(str))
+		}
+
+		// Value stores the UUID as its canonical string
+		func (u UUID) Value() (driver.Value, error) {
+			return u.String(), nil
+		}
+
+		// Scan accepts the canonical string or the 16 raw bytes, NULL is the nil UUID
+		func (u *UUID) Scan(src any) error {
+			switch src := src.(type) {
+			case nil:
+				*u = UUID{}
+				return nil
+			case string:
+				return u.UnmarshalText([]byte(src))
+			case []byte:
+				if len(src) == len(u) {
+					copy(u[:], src)
+					return nil
+				}
+				return u.UnmarshalText(src)
+			default:
+				return fmt.Errorf("can't scan %T into a UUID", src)
+			}
 		}
 	`
 )
 
+// GenBoilerPlate writes the shared OData types to Filename in the current directory
 func GenBoilerPlate(packageName string) error {
+	return GenBoilerPlateIn(".", packageName)
+}
+
+// GenBoilerPlateIn writes the shared OData types to Filename in dir
+func GenBoilerPlateIn(dir string, packageName string) error {
+	content, err := BoilerPlate(packageName, Options{})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, Filename), content, 0o644)
+}
+
+// Options are the optional parts of the boilerplate
+type Options struct {
+	// Client is the client that typed links use
+	Client bool
+	// Server is the router that generated resource handlers register with
+	Server bool
+	// FieldVersions is ValidFields, which uses the versions the generated types register
+	FieldVersions bool
+}
+
+// clientImports, serverImports and fieldVersionsImports are the extra imports ClientText,
+// ServerText and FieldVersionsText need
+var (
+	clientImports        = []string{"context", "io", "net/http"}
+	serverImports        = []string{"net/http"}
+	fieldVersionsImports = []string{"slices"}
+)
+
+// BoilerPlate returns the formatted source for the shared OData types along with the optional parts
+func BoilerPlate(packageName string, options Options) ([]byte, error) {
 	fileToken := &ast.File{
 		Name: ast.NewIdent(packageName),
 		Decls: []ast.Decl{
@@ -184,7 +1353,19 @@ func GenBoilerPlate(packageName string) error {
 					&ast.ImportSpec{
 						Path: &ast.BasicLit{
 							Kind:  token.STRING,
-							Value: `"bytes"`,
+							Value: `"database/sql/driver"`,
+						},
+					},
+					&ast.ImportSpec{
+						Path: &ast.BasicLit{
+							Kind:  token.STRING,
+							Value: `"encoding/hex"`,
+						},
+					},
+					&ast.ImportSpec{
+						Path: &ast.BasicLit{
+							Kind:  token.STRING,
+							Value: `"encoding/json"`,
 						},
 					},
 					&ast.ImportSpec{
@@ -196,7 +1377,25 @@ func GenBoilerPlate(packageName string) error {
 					&ast.ImportSpec{
 						Path: &ast.BasicLit{
 							Kind:  token.STRING,
-							Value: `"math/big"`,
+							Value: `"fmt"`,
+						},
+					},
+					&ast.ImportSpec{
+						Path: &ast.BasicLit{
+							Kind:  token.STRING,
+							Value: `"math"`,
+						},
+					},
+					&ast.ImportSpec{
+						Path: &ast.BasicLit{
+							Kind:  token.STRING,
+							Value: `"net/url"`,
+						},
+					},
+					&ast.ImportSpec{
+						Path: &ast.BasicLit{
+							Kind:  token.STRING,
+							Value: `"regexp"`,
 						},
 					},
 					&ast.ImportSpec{
@@ -205,6 +1404,18 @@ func GenBoilerPlate(packageName string) error {
 							Value: `"strconv"`,
 						},
 					},
+					&ast.ImportSpec{
+						Path: &ast.BasicLit{
+							Kind:  token.STRING,
+							Value: `"strings"`,
+						},
+					},
+					&ast.ImportSpec{
+						Path: &ast.BasicLit{
+							Kind:  token.STRING,
+							Value: `"sync"`,
+						},
+					},
 					&ast.ImportSpec{
 						Path: &ast.BasicLit{
 							Kind:  token.STRING,
@@ -263,59 +1474,139 @@ func GenBoilerPlate(packageName string) error {
 						},
 					},
 					&ast.TypeSpec{
-						Name: ast.NewIdent("Duration"),
+						Name: ast.NewIdent("Date"),
 						Type: &ast.StructType{
 							Fields: &ast.FieldList{
 								List: []*ast.Field{
 									{
-										Names: []*ast.Ident{ast.NewIdent("Duration")},
-										Type:  &ast.Ident{Name: "time.Duration"},
+										Names: []*ast.Ident{ast.NewIdent("Time")},
+										Type:  &ast.Ident{Name: "time.Time"},
 									},
 								},
 							},
 						},
 					},
 					&ast.TypeSpec{
-						Name: ast.NewIdent("UUID"),
+						Name: ast.NewIdent("TimeOfDay"),
+						Type: &ast.StructType{
+							Fields: &ast.FieldList{
+								List: []*ast.Field{
+									{
+										Names: []*ast.Ident{ast.NewIdent("Time")},
+										Type:  &ast.Ident{Name: "time.Time"},
+									},
+								},
+							},
+						},
+					},
+					&ast.TypeSpec{
+						Name: ast.NewIdent("Duration"),
 						Type: &ast.StructType{
 							Fields: &ast.FieldList{
 								List: []*ast.Field{
 									{
-										Names: []*ast.Ident{ast.NewIdent("Data")},
-										Type:  &ast.Ident{Name: "big.Int"},
+										Names: []*ast.Ident{ast.NewIdent("Years")},
+										Type:  &ast.Ident{Name: "int"},
+									},
+									{
+										Names: []*ast.Ident{ast.NewIdent("Months")},
+										Type:  &ast.Ident{Name: "int"},
+									},
+									{
+										Names: []*ast.Ident{ast.NewIdent("Duration")},
+										Type:  &ast.Ident{Name: "time.Duration"},
 									},
 								},
 							},
 						},
 					},
+					&ast.TypeSpec{
+						Name: ast.NewIdent("UUID"),
+						Type: &ast.ArrayType{
+							Len: &ast.BasicLit{Kind: token.INT, Value: "16"},
+							Elt: ast.NewIdent("byte"),
+						},
+					},
 				},
 			},
 		},
 	}
+	extraImports := []string{}
+	if options.Client {
+		extraImports = append(extraImports, clientImports...)
+	}
+	if options.Server {
+		extraImports = append(extraImports, serverImports...)
+	}
+	if options.FieldVersions {
+		extraImports = append(extraImports, fieldVersionsImports...)
+	}
+	slices.Sort(extraImports)
+	imports := fileToken.Decls[0].(*ast.GenDecl)
+	for _, path := range slices.Compact(extraImports) {
+		imports.Specs = append(imports.Specs, &ast.ImportSpec{
+			Path: &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(path)},
+		})
+	}
 	buf := bytes.NewBuffer(nil)
 	fileSet := token.NewFileSet()
 	err := format.Node(buf, fileSet, fileToken)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	_, err = buf.WriteString(DateTimeOffsetMarshalJSONText)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	_, err = buf.WriteString(DurationMarshalJSONText)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	file, err := os.Create(Filename)
+	_, err = buf.WriteString(UUIDMarshalJSONText)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	//nolint:errcheck // Ignore error on close, not sure what we can do about it
-	defer file.Close()
-	content, err := format.Source(buf.Bytes())
+	_, err = buf.WriteString(CollectionText)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	_, err = buf.WriteString(ValidationText)
+	if err != nil {
+		return nil, err
+	}
+	_, err = buf.WriteString(PermissionText)
+	if err != nil {
+		return nil, err
+	}
+	_, err = buf.WriteString(URIText)
+	if err != nil {
+		return nil, err
+	}
+	_, err = buf.WriteString(EnumText)
+	if err != nil {
+		return nil, err
+	}
+	_, err = buf.WriteString(DecodeText)
+	if err != nil {
+		return nil, err
+	}
+	if options.Client {
+		_, err = buf.WriteString(ClientText)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if options.Server {
+		_, err = buf.WriteString(ServerText)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if options.FieldVersions {
+		_, err = buf.WriteString(FieldVersionsText)
+		if err != nil {
+			return nil, err
+		}
 	}
-	_, err = file.Write(content)
-	return err
+	return format.Source(buf.Bytes())
 }
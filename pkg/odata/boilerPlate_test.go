@@ -0,0 +1,290 @@
+package odata
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// goTest runs go test in a module made of the boilerplate and the test files, since the boilerplate
+// is source text rather than code in this package
+func goTest(t *testing.T, options Options, files map[string]string) {
+	t.Helper()
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go isn't installed")
+	}
+	boilerPlate, err := BoilerPlate("odata", options)
+	if err != nil {
+		t.Fatalf("BoilerPlate: %v", err)
+	}
+	dir := t.TempDir()
+	files["go.mod"] = "module example.com/odata\n\ngo 1.23\n"
+	files[Filename] = string(boilerPlate)
+	for name, content := range files {
+		err = os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	cmd := exec.Command(goBin, "test", "./...")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("go test of the boilerplate failed: %v\n%s", err, out)
+	}
+}
+
+const durationTest = `package odata
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"testing/quick"
+	"time"
+)
+
+func TestDurationRoundTrip(t *testing.T) {
+	roundTrip := func(d Duration) bool {
+		text, err := d.MarshalText()
+		if err != nil {
+			t.Errorf("MarshalText(%#v): %v", d, err)
+			return false
+		}
+		parsed, err := ParseDuration(string(text))
+		if err != nil {
+			t.Errorf("ParseDuration(%q): %v", text, err)
+			return false
+		}
+		if parsed != d {
+			t.Errorf("ParseDuration(%q) = %#v, want %#v", text, parsed, d)
+			return false
+		}
+		return true
+	}
+	// Years, months and the duration have to share a sign to have an ISO 8601 form
+	random := func(years uint16, months uint16, duration int64, yearMonths bool) bool {
+		d := Duration{Duration: time.Duration(duration)}
+		if yearMonths {
+			d.Years, d.Months = int(years), int(months)
+			if duration < 0 {
+				d.Years, d.Months = -d.Years, -d.Months
+			}
+		}
+		return roundTrip(d)
+	}
+	config := &quick.Config{MaxCount: 100000, Rand: rand.New(rand.NewSource(1))}
+	err := quick.Check(random, config)
+	if err != nil {
+		t.Error(err)
+	}
+	for _, d := range []time.Duration{0, 1, -1, math.MaxInt64, math.MinInt64, time.Second, -time.Hour, 24 * time.Hour} {
+		roundTrip(Duration{Duration: d})
+	}
+}
+
+func TestDurationSpecExamples(t *testing.T) {
+	tests := []struct {
+		text string
+		want Duration
+	}{
+		{"P1DT2H", Duration{Duration: 26 * time.Hour}},
+		{"-PT0.5S", Duration{Duration: -500 * time.Millisecond}},
+		{"PT0S", Duration{}},
+		{"P1Y2M", Duration{Years: 1, Months: 2}},
+	}
+	for _, test := range tests {
+		d, err := ParseDuration(test.text)
+		if err != nil || d != test.want {
+			t.Errorf("ParseDuration(%q) = %#v, %v, want %#v", test.text, d, err, test.want)
+		}
+		b, err := test.want.MarshalJSON()
+		if err != nil || string(b) != "\"" + test.text + "\"" {
+			t.Errorf("%#v.MarshalJSON() = %s, %v, want %q", test.want, b, err, test.text)
+		}
+	}
+}
+`
+
+func TestDuration(t *testing.T) {
+	goTest(t, Options{}, map[string]string{"duration_test.go": durationTest})
+}
+
+const validFieldsTest = `package odata
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestValidFields(t *testing.T) {
+	fieldVersions["Chassis.Chassis"] = map[string]string{"Id": "", "Location": "v1_2_0"}
+	fieldVersions["Contoso.Manager.Manager"] = map[string]string{"Id": "", "Oem": "v1_1_0"}
+	fieldVersions["Contoso.vendor.Thing"] = map[string]string{"Id": "", "Oem": "v1_1_0"}
+	tests := map[string][]string{
+		"#Chassis.v1_0_0.Chassis":         {"Id"},
+		"#Chassis.v1_2_0.Chassis":         {"Id", "Location"},
+		"#Chassis.Chassis":                {"Id", "Location"},
+		"#Contoso.Manager.v1_0_0.Manager": {"Id"},
+		"#Contoso.Manager.v1_1_0.Manager": {"Id", "Oem"},
+		"#Contoso.Manager.Manager":        {"Id", "Oem"},
+		"#Contoso.vendor.Thing":           {"Id", "Oem"},
+	}
+	for odataType, want := range tests {
+		fields, ok := ValidFields(odataType)
+		if !ok || !slices.Equal(fields, want) {
+			t.Errorf("ValidFields(%q) = %v, %t, want %v", odataType, fields, ok, want)
+		}
+	}
+	_, ok := ValidFields("#Contoso.v1_0_0.Manager")
+	if ok {
+		t.Error("ValidFields found a type that wasn't generated")
+	}
+}
+`
+
+func TestValidFields(t *testing.T) {
+	goTest(t, Options{FieldVersions: true}, map[string]string{"validfields_test.go": validFieldsTest})
+}
+
+const temporalTest = `package odata
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestTemporalWithoutSeconds(t *testing.T) {
+	var timeOfDay TimeOfDay
+	err := json.Unmarshal([]byte(` + "`" + `"10:30"` + "`" + `), &timeOfDay)
+	if err != nil || timeOfDay.String() != "10:30:00" {
+		t.Errorf("got TimeOfDay %s, %v, want 10:30:00", timeOfDay, err)
+	}
+	var dateTime DateTimeOffset
+	err = json.Unmarshal([]byte(` + "`" + `"2024-01-01T10:00Z"` + "`" + `), &dateTime)
+	if err != nil || !dateTime.Time.Equal(time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("got DateTimeOffset %s, %v, want 2024-01-01T10:00:00Z", dateTime.Time, err)
+	}
+	err = json.Unmarshal([]byte(` + "`" + `"2024-01-01T10:00+01:00"` + "`" + `), &dateTime)
+	if err != nil || !dateTime.Time.Equal(time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("got DateTimeOffset %s, %v, want 2024-01-01T09:00:00Z", dateTime.Time, err)
+	}
+	err = json.Unmarshal([]byte(` + "`" + `"10"` + "`" + `), &timeOfDay)
+	if err == nil {
+		t.Error("TimeOfDay accepted 10")
+	}
+}
+`
+
+func TestTemporal(t *testing.T) {
+	goTest(t, Options{}, map[string]string{"temporal_test.go": temporalTest})
+}
+
+const handleTest = `package odata
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandleSharedPattern(t *testing.T) {
+	mux := &serveMux{mux: http.NewServeMux(), patterns: map[string][]route{}}
+	for _, uri := range []URITemplate{"/redfish/v1/Things/{ThingId}.json", "/redfish/v1/Things/{ThingId}.xml", "/redfish/v1/Things/{Id}/Parts/{PartId}", "/redfish/v1/Things/{ThingId}/Parts/{Name}/"} {
+		handle(mux, http.MethodGet, uri, func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.WriteString(w, string(uri)+" "+r.PathValue("ThingId")+r.PathValue("Id"))
+		})
+	}
+	tests := map[string]string{
+		"/redfish/v1/Things/1.json":     "/redfish/v1/Things/{ThingId}.json 1",
+		"/redfish/v1/Things/2.xml":      "/redfish/v1/Things/{ThingId}.xml 2",
+		"/redfish/v1/Things/3/Parts/4":  "/redfish/v1/Things/{Id}/Parts/{PartId} 3",
+		"/redfish/v1/Things/3/Parts/4/": "/redfish/v1/Things/{ThingId}/Parts/{Name}/ 3",
+	}
+	for uri, want := range tests {
+		recorder := httptest.NewRecorder()
+		mux.mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, uri, nil))
+		if recorder.Code != http.StatusOK || recorder.Body.String() != want {
+			t.Errorf("GET %s = %d %q, want %q", uri, recorder.Code, recorder.Body, want)
+		}
+	}
+	recorder := httptest.NewRecorder()
+	mux.mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/redfish/v1/Things/1.txt", nil))
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("GET /redfish/v1/Things/1.txt = %d, want 404", recorder.Code)
+	}
+}
+
+func TestHandleFragment(t *testing.T) {
+	mux := &serveMux{mux: http.NewServeMux(), patterns: map[string][]route{}}
+	handle(mux, http.MethodGet, "/redfish/v1/Chassis/{ChassisId}/Power#/PowerControl/{PowerControlId}", func(w http.ResponseWriter, r *http.Request) {})
+	recorder := httptest.NewRecorder()
+	mux.mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/redfish/v1/Chassis/1/Power", nil))
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("the route for a fragment served its resource with %d", recorder.Code)
+	}
+}
+`
+
+func TestHandle(t *testing.T) {
+	goTest(t, Options{Server: true}, map[string]string{"handle_test.go": handleTest})
+}
+
+const uriTest = `package odata
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestURITemplate(t *testing.T) {
+	power := URITemplate("/redfish/v1/Chassis/{ChassisId}/Power#/PowerControl/{PowerControlId}")
+	export := URITemplate("/redfish/v1/Chassis/{ChassisId}.json")
+	tests := []struct {
+		template URITemplate
+		uri      string
+		want     map[string]string
+	}{
+		{power, "/redfish/v1/Chassis/1/Power#/PowerControl/0", map[string]string{"ChassisId": "1", "PowerControlId": "0"}},
+		{power, "/redfish/v1/Chassis/1/Power?$select=Id#/PowerControl/0/", map[string]string{"ChassisId": "1", "PowerControlId": "0"}},
+		{power, "/redfish/v1/Chassis/1/Power", nil},
+		{power, "/redfish/v1/Chassis/1/Power#/Voltages/0", nil},
+		{power, "/redfish/v1/Chassis/1/Power#/PowerControl/0/Status", nil},
+		{export, "/redfish/v1/Chassis/Rack%201.json", map[string]string{"ChassisId": "Rack 1"}},
+		{export, "/redfish/v1/Chassis/1.json?only", map[string]string{"ChassisId": "1"}},
+		{export, "/redfish/v1/Chassis/.json", nil},
+		{export, "/redfish/v1/Chassis/1.xml", nil},
+		{export, "/redfish/v1/Chassis/1.json#/Thermal", nil},
+		{"/redfish/v1/Chassis/{ChassisId}", "/redfish/v1/Chassis/1/", map[string]string{"ChassisId": "1"}},
+		{"/redfish/v1/Chassis/{ChassisId}", "/redfish/v1/Chassis/1/Power", nil},
+	}
+	for _, test := range tests {
+		got, ok := test.template.Match(test.uri)
+		if ok != (test.want != nil) || !maps.Equal(got, test.want) {
+			t.Errorf("%s.Match(%q) = %v, %t, want %v", test.template, test.uri, got, ok, test.want)
+		}
+	}
+	if got := power.Expand("1", "0"); got != "/redfish/v1/Chassis/1/Power#/PowerControl/0" {
+		t.Errorf("Expand = %s", got)
+	}
+	if got := export.Expand("Rack 1"); got != "/redfish/v1/Chassis/Rack%201.json" {
+		t.Errorf("Expand = %s", got)
+	}
+	if got := power.Params(); !slices.Equal(got, []string{"ChassisId", "PowerControlId"}) {
+		t.Errorf("Params = %v", got)
+	}
+	values, ok := matchURIs([]URITemplate{export, power}, "/redfish/v1/Chassis/2/Power#/PowerControl/1")
+	if !ok || values["PowerControlId"] != "1" {
+		t.Errorf("matchURIs = %v, %t", values, ok)
+	}
+}
+`
+
+func TestURITemplate(t *testing.T) {
+	goTest(t, Options{}, map[string]string{"uri_test.go": uriTest})
+}